@@ -216,6 +216,36 @@ func addMetadata(writer *zip.Writer) error {
 	return nil
 }
 
+func addDiagnostics(writer *zip.Writer) error {
+	diagnostics := make([]*ficsitcli.InstallationDiagnostics, 0)
+	for _, install := range ficsitcli.FicsitCLI.GetInstallations() {
+		// Debug info is generated to investigate failed applies, so write access is checked too
+		installDiagnostics, err := ficsitcli.FicsitCLI.DiagnoseInstallation(install, true)
+		if err != nil {
+			slog.Warn("failed to diagnose installation", slog.String("path", install), slog.Any("error", err))
+			continue
+		}
+		installDiagnostics.Install = utils.RedactPath(installDiagnostics.Install)
+		diagnostics = append(diagnostics, installDiagnostics)
+	}
+
+	diagnosticsBytes, err := utils.JSONMarshal(diagnostics, 2)
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnostics: %w", err)
+	}
+
+	diagnosticsFile, err := writer.Create("diagnostics.json")
+	if err != nil {
+		return fmt.Errorf("failed to create diagnostics file: %w", err)
+	}
+
+	_, err = diagnosticsFile.Write(diagnosticsBytes)
+	if err != nil {
+		return fmt.Errorf("failed to write diagnostics: %w", err)
+	}
+	return nil
+}
+
 func (a *app) generateAndSaveDebugInfo(filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
@@ -232,6 +262,11 @@ func (a *app) generateAndSaveDebugInfo(filename string) error {
 		slog.Warn("failed to add metadata to debuginfo zip", slog.Any("error", err))
 	}
 
+	err = addDiagnostics(writer)
+	if err != nil {
+		slog.Warn("failed to add diagnostics to debuginfo zip", slog.Any("error", err))
+	}
+
 	// Add SMM log last, as it may list errors from previous steps
 	err = utils.AddFileToZip(writer, viper.GetString("log-file"), "SatisfactoryModManager.log")
 	if err != nil {
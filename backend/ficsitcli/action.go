@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
@@ -23,11 +24,105 @@ type taskUpdate struct {
 	progress utils.Progress
 }
 
-func (f *ficsitCLI) action(action Action, item ProgressItem, run func(*slog.Logger, chan<- taskUpdate) error) error {
-	if !f.actionMutex.TryLock() {
-		return fmt.Errorf("another operation in progress")
+// actionScope is what an action modifies. Actions with overlapping scopes cannot run at the same time
+type actionScope struct {
+	installs []string
+	profiles []string
+}
+
+func (s actionScope) with(other actionScope) actionScope {
+	return actionScope{
+		installs: append(slices.Clone(s.installs), other.installs...),
+		profiles: append(slices.Clone(s.profiles), other.profiles...),
+	}
+}
+
+func (s actionScope) lockKeys() []string {
+	keys := make([]string, 0, len(s.installs)+len(s.profiles))
+	for _, install := range s.installs {
+		keys = append(keys, "install:"+install)
+	}
+	for _, profile := range s.profiles {
+		keys = append(keys, "profile:"+profile)
+	}
+	// Always lock in the same order
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// installScope is the scope of an action on an install that does not change its profile
+func installScope(install string) actionScope {
+	return actionScope{installs: []string{install}}
+}
+
+// profileScope is the scope of an action that changes the profile, and applies it to all installs using it.
+// The installs using it are added when the scope is locked
+func profileScope(profile string) actionScope {
+	return actionScope{profiles: []string{profile}}
+}
+
+// installProfileScope is the scope of an action on an install that changes its profile,
+// and applies it to the install and all other installs using the profile
+func installProfileScope(installation *cli.Installation) actionScope {
+	return installScope(installation.Path).with(profileScope(installation.Profile))
+}
+
+// lockScope locks the scope, adding the installs using its profiles.
+// Installs only change profile while both profiles are locked, and the installs are found while holding the state lock,
+// so the returned scope has all the installs affected by the action.
+// Every action changes specific installs or profiles, so an empty scope is rejected instead of locking nothing
+func (f *ficsitCLI) lockScope(scope actionScope) (actionScope, func(), error) {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	for _, profile := range scope.profiles {
+		for _, installation := range f.ficsitCli.Installations.Installations {
+			if installation.Profile == profile {
+				scope.installs = append(scope.installs, installation.Path)
+			}
+		}
+	}
+
+	if len(scope.installs) == 0 && len(scope.profiles) == 0 {
+		return scope, nil, fmt.Errorf("operation has no installs or profiles to lock")
+	}
+
+	locked := []*sync.Mutex{}
+	unlock := func() {
+		for _, mutex := range locked {
+			mutex.Unlock()
+		}
+	}
+	for _, key := range scope.lockKeys() {
+		mutex, _ := f.actionLocks.LoadOrCompute(key, func() *sync.Mutex {
+			return &sync.Mutex{}
+		})
+		if !mutex.TryLock() {
+			unlock()
+			return scope, nil, fmt.Errorf("another operation in progress on %s", key)
+		}
+		locked = append(locked, mutex)
+	}
+	return scope, unlock, nil
+}
+
+// updateState runs update while holding the state lock.
+// Actions on different scopes run at the same time, so every change to the ficsit-cli profiles and installations,
+// and saving them, must happen in it
+func (f *ficsitCLI) updateState(update func() error) error {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+	return update()
+}
+
+func (f *ficsitCLI) action(action Action, item ProgressItem, scope actionScope, run func(*slog.Logger, chan<- taskUpdate) error) error {
+	scope, unlock, err := f.lockScope(scope)
+	if err != nil {
+		return err
 	}
-	defer f.actionMutex.Unlock()
+	defer unlock()
+
+	undoSnapshot := f.snapshotScope(action, item, scope)
 
 	var logAttrs []any
 	logAttrs = append(logAttrs, slog.String("type", string(action)))
@@ -42,11 +137,14 @@ func (f *ficsitCLI) action(action Action, item ProgressItem, run func(*slog.Logg
 	done := make(chan bool)
 	defer close(done)
 
-	progress := newProgress(action, item)
+	installs := slices.Clone(scope.installs)
+	slices.Sort(installs)
+	progress := newProgress(action, item, slices.Compact(installs))
+	f.startProgress(progress)
+
 	tasks := xsync.NewMapOf[string, utils.Progress]()
 	go func() {
-		wailsRuntime.EventsEmit(common.AppContext, "progress", progress)
-		defer wailsRuntime.EventsEmit(common.AppContext, "progress", nil)
+		defer f.endProgress(progress)
 
 		progressTicker := time.NewTicker(100 * time.Millisecond)
 		defer progressTicker.Stop()
@@ -56,11 +154,13 @@ func (f *ficsitCLI) action(action Action, item ProgressItem, run func(*slog.Logg
 			case <-done:
 				return
 			case <-progressTicker.C:
+				f.progressMutex.Lock()
 				tasks.Range(func(key string, value utils.Progress) bool {
 					progress.Tasks[key] = value
 					return true
 				})
-				wailsRuntime.EventsEmit(common.AppContext, "progress", progress)
+				f.progressMutex.Unlock()
+				f.emitProgress()
 			}
 		}
 	}()
@@ -72,7 +172,14 @@ func (f *ficsitCLI) action(action Action, item ProgressItem, run func(*slog.Logg
 		}
 	}()
 
-	err := run(l, taskChannel)
+	err = run(l, taskChannel)
+
+	f.recordUndo(undoSnapshot, err)
+
+	for _, install := range scope.installs {
+		f.rewatchInstallation(install)
+	}
+
 	if err != nil {
 		l.Info("action failed")
 		return err
@@ -83,16 +190,66 @@ func (f *ficsitCLI) action(action Action, item ProgressItem, run func(*slog.Logg
 	return nil
 }
 
+func (f *ficsitCLI) startProgress(progress *Progress) {
+	f.progressMutex.Lock()
+	f.runningActions = append(f.runningActions, progress)
+	f.progressMutex.Unlock()
+	f.emitProgress()
+}
+
+func (f *ficsitCLI) endProgress(progress *Progress) {
+	f.progressMutex.Lock()
+	f.runningActions = slices.DeleteFunc(f.runningActions, func(p *Progress) bool {
+		return p == progress
+	})
+	f.progressMutex.Unlock()
+	f.emitProgress()
+}
+
+// emitProgress sends the progress of each install, and the combined progress of all running actions.
+// The combined progress is the oldest running action, with the tasks of all of them
+func (f *ficsitCLI) emitProgress() {
+	f.progressMutex.Lock()
+	defer f.progressMutex.Unlock()
+
+	installsProgress := make(map[string]*Progress)
+	var combined *Progress
+	for _, progress := range f.runningActions {
+		snapshot := newProgress(progress.Action, progress.Item, progress.Installs)
+		maps.Copy(snapshot.Tasks, progress.Tasks)
+		for _, install := range progress.Installs {
+			installsProgress[install] = snapshot
+		}
+
+		if combined == nil {
+			combined = newProgress(progress.Action, progress.Item, nil)
+		}
+		combined.Installs = append(combined.Installs, progress.Installs...)
+		maps.Copy(combined.Tasks, progress.Tasks)
+	}
+
+	wailsRuntime.EventsEmit(common.AppContext, "installsProgress", installsProgress)
+	if combined == nil {
+		wailsRuntime.EventsEmit(common.AppContext, "progress", nil)
+	} else {
+		wailsRuntime.EventsEmit(common.AppContext, "progress", combined)
+	}
+}
+
 func (f *ficsitCLI) Apply() error {
 	profileName := f.GetSelectedProfile()
 	if profileName == nil {
 		return fmt.Errorf("no profile selected")
 	}
-	return f.action(ActionApply, newSimpleItem(*profileName), f.apply)
+	selectedInstallation := f.GetSelectedInstall()
+	return f.action(ActionApply, newSimpleItem(*profileName), installProfileScope(selectedInstallation), func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		return f.apply(l, selectedInstallation, taskChannel)
+	})
 }
 
-func (f *ficsitCLI) apply(l *slog.Logger, taskChannel chan<- taskUpdate) error {
-	installsToApply, profile, err := f.getInstallsToApply()
+// apply installs the profile of the given install on it, and on all other modded installs using the same profile
+func (f *ficsitCLI) apply(l *slog.Logger, selectedInstall *cli.Installation, taskChannel chan<- taskUpdate) error {
+	installsToApply, profile, err := f.getInstallsToApply(selectedInstall)
 	if err != nil {
 		return err
 	}
@@ -102,17 +259,25 @@ func (f *ficsitCLI) apply(l *slog.Logger, taskChannel chan<- taskUpdate) error {
 		targetsUsingProfile[resolver.TargetName(install.targetName)] = true
 	}
 
-	profile.RequiredTargets = maps.Keys(targetsUsingProfile)
-	err = f.ficsitCli.Profiles.Save()
-	if err != nil {
-		l.Error("failed to save profile", slog.Any("error", err))
-	}
+	_ = f.updateState(func() error {
+		profile.RequiredTargets = maps.Keys(targetsUsingProfile)
+		err := f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
 
 	f.EmitModsChange()
 	defer f.EmitModsChange()
 
 	defer close(taskChannel)
 
+	return f.installTargets(installsToApply, taskChannel)
+}
+
+// installTargets installs the installs concurrently, forwarding their progress to the task channel
+func (f *ficsitCLI) installTargets(installsToApply []installWithTarget, taskChannel chan<- taskUpdate) error {
 	var errg errgroup.Group
 	var wg sync.WaitGroup
 
@@ -146,7 +311,9 @@ func (f *ficsitCLI) apply(l *slog.Logger, taskChannel chan<- taskUpdate) error {
 				}
 			}()
 
-			installErr := installTarget.install.Install(f.ficsitCli, installChannel)
+			installErr := f.recordApply(installTarget.install, func() error {
+				return installTarget.install.Install(f.ficsitCli, installChannel) //nolint:wrapcheck
+			})
 			if installErr != nil {
 				var solvingError resolver.DependencyResolverError
 				if errors.As(installErr, &solvingError) {
@@ -172,8 +339,7 @@ type installWithTarget struct {
 	targetName string
 }
 
-func (f *ficsitCLI) getInstallsToApply() ([]installWithTarget, *cli.Profile, error) {
-	selectedInstall := f.GetSelectedInstall()
+func (f *ficsitCLI) getInstallsToApply(selectedInstall *cli.Installation) ([]installWithTarget, *cli.Profile, error) {
 	if selectedInstall == nil {
 		return nil, nil, fmt.Errorf("no installation selected")
 	}
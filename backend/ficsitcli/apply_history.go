@@ -0,0 +1,429 @@
+package ficsitcli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	"github.com/satisfactorymodding/ficsit-cli/cli/disk"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+	"github.com/spf13/viper"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
+)
+
+const applyHistorySize = 20
+
+type FileChangeType string
+
+const (
+	FileChangeCreated  FileChangeType = "created"
+	FileChangeReplaced FileChangeType = "replaced"
+	FileChangeDeleted  FileChangeType = "deleted"
+)
+
+var AllFileChangeTypes = []struct {
+	Value  FileChangeType
+	TSName string
+}{
+	{FileChangeCreated, "CREATED"},
+	{FileChangeReplaced, "REPLACED"},
+	{FileChangeDeleted, "DELETED"},
+}
+
+// FileChange is a file of the Mods directory that an apply changed.
+// The path is relative to the Mods directory. Hashes are sha256. The new hash is known unless reading the file failed.
+// The old hash is best-effort: it is only known if an earlier recorded apply wrote the file,
+// and the file has not changed since, since hashing every file before each apply would be too slow
+type FileChange struct {
+	Path    string         `json:"path"`
+	Type    FileChangeType `json:"type"`
+	OldSize int64          `json:"oldSize,omitempty"`
+	OldHash string         `json:"oldHash,omitempty"`
+	NewSize int64          `json:"newSize,omitempty"`
+	NewHash string         `json:"newHash,omitempty"`
+}
+
+// ApplyRecord is an apply to an install, with the lockfile it resulted in and the files it changed
+type ApplyRecord struct {
+	ID       string             `json:"id"`
+	Time     time.Time          `json:"time"`
+	Install  string             `json:"install"`
+	Profile  string             `json:"profile"`
+	Lockfile *resolver.LockFile `json:"lockfile"`
+	Changes  []FileChange       `json:"changes"`
+	// Error is set when the apply failed, the changes are the ones made before it failed
+	Error string `json:"error,omitempty"`
+}
+
+// fileState is what the directory listing tells about a file. Files are only read to hash the ones that changed
+type fileState struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// knownFile is the state and hash of a file of the Mods directory when an apply last changed it,
+// so the old hash of the file is known when a later apply replaces or deletes it
+type knownFile struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Hash    string    `json:"hash"`
+}
+
+var applyHistoryMutex sync.Mutex
+
+func installPathHash(path string) string {
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:8])
+}
+
+func applyHistoryPath(installPath string) string {
+	return filepath.Join(viper.GetString("smm-local-dir"), "apply-history", installPathHash(installPath)+".json")
+}
+
+func knownFilesPath(installPath string) string {
+	return filepath.Join(viper.GetString("smm-local-dir"), "apply-history", installPathHash(installPath)+".files.json")
+}
+
+// recordApply runs the apply of the install, and records the files it changed in the install's apply history.
+// Failed applies are recorded too, since they may have changed some files before failing
+func (f *ficsitCLI) recordApply(installation *cli.Installation, apply func() error) error {
+	l := slog.With(slog.String("task", "recordApply"), slog.String("install", installation.Path))
+
+	d, err := installation.GetDisk()
+	if err != nil {
+		l.Warn("failed to get disk, not recording apply", slog.Any("error", err))
+		return apply()
+	}
+	modsDirectory := filepath.Join(installation.BasePath(), "FactoryGame", "Mods")
+
+	before, err := snapshotModsDirectory(d, modsDirectory)
+	if err != nil {
+		l.Warn("failed to list files before apply, not recording apply", slog.Any("error", err))
+		return apply()
+	}
+
+	applyErr := apply()
+
+	after, err := snapshotModsDirectory(d, modsDirectory)
+	if err != nil {
+		l.Warn("failed to list files after apply, not recording apply", slog.Any("error", err))
+		return applyErr
+	}
+
+	changes := diffModsDirectory(before, after)
+	hashChanges(l, d, installation.Path, modsDirectory, changes, before, after)
+
+	record := ApplyRecord{
+		ID:      uuid.NewString(),
+		Time:    time.Now().UTC(),
+		Install: installation.Path,
+		Profile: installation.Profile,
+		Changes: changes,
+	}
+	if applyErr != nil {
+		record.Error = applyErr.Error()
+	}
+	record.Lockfile, err = installation.LockFile(f.ficsitCli)
+	if err != nil {
+		l.Warn("failed to read lockfile", slog.Any("error", err))
+	}
+
+	if err := saveApplyRecord(record); err != nil {
+		l.Error("failed to save apply record", slog.Any("error", err))
+	}
+
+	return applyErr
+}
+
+// isLocalPath returns whether the install path is on this machine, rather than an FTP or SFTP server
+func isLocalPath(path string) bool {
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return true
+	}
+	return parsed.Scheme != "ftp" && parsed.Scheme != "sftp"
+}
+
+// hashChanges fills in the hashes of the changed files, and records them as the install's known files.
+// New files are hashed, old hashes are taken from the known files if the file is unchanged since it was recorded.
+// Files of remote installs are read through the disk, which downloads them, but only the changed files are read
+func hashChanges(l *slog.Logger, d disk.Disk, installPath string, modsDirectory string, changes []FileChange, before map[string]fileState, after map[string]fileState) {
+	if len(changes) == 0 {
+		return
+	}
+
+	applyHistoryMutex.Lock()
+	defer applyHistoryMutex.Unlock()
+
+	known, err := readKnownFiles(installPath)
+	if err != nil {
+		l.Warn("discarding known file hashes", slog.Any("error", err))
+		known = make(map[string]knownFile)
+	}
+
+	for i := range changes {
+		change := &changes[i]
+		if change.Type != FileChangeCreated {
+			oldState := before[change.Path]
+			if knownState, ok := known[change.Path]; ok && knownState.Size == oldState.Size && knownState.ModTime.Equal(oldState.ModTime) {
+				change.OldHash = knownState.Hash
+			}
+		}
+		if change.Type == FileChangeDeleted {
+			delete(known, change.Path)
+			continue
+		}
+		fullPath := filepath.Join(modsDirectory, change.Path)
+		hash, err := hashFile(d, installPath, fullPath)
+		if err != nil {
+			l.Warn("failed to hash file", slog.String("path", change.Path), slog.Any("error", err))
+			delete(known, change.Path)
+			continue
+		}
+		change.NewHash = hash
+		newState := after[change.Path]
+		known[change.Path] = knownFile{Size: newState.Size, ModTime: newState.ModTime, Hash: hash}
+	}
+
+	if err := saveKnownFiles(installPath, known); err != nil {
+		l.Warn("failed to save known file hashes", slog.Any("error", err))
+	}
+}
+
+// hashFile hashes a file of the install. Local files are streamed, remote ones are read through the disk
+func hashFile(d disk.Disk, installPath string, path string) (string, error) {
+	if isLocalPath(installPath) {
+		return hashLocalFile(path)
+	}
+	data, err := d.Read(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func hashLocalFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readKnownFiles reads the known files of the install. The apply history lock must be held
+func readKnownFiles(installPath string) (map[string]knownFile, error) {
+	known := make(map[string]knownFile)
+	data, err := os.ReadFile(knownFilesPath(installPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return known, nil
+		}
+		return nil, fmt.Errorf("failed to read known files: %w", err)
+	}
+	if err := json.Unmarshal(data, &known); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal known files: %w", err)
+	}
+	return known, nil
+}
+
+// saveKnownFiles saves the known files of the install. The apply history lock must be held
+func saveKnownFiles(installPath string, known map[string]knownFile) error {
+	data, err := utils.JSONMarshal(known, 2)
+	if err != nil {
+		return fmt.Errorf("failed to marshal known files: %w", err)
+	}
+
+	knownPath := knownFilesPath(installPath)
+	if err := os.MkdirAll(filepath.Dir(knownPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create apply history directory: %w", err)
+	}
+	if err := os.WriteFile(knownPath, data, 0o755); err != nil {
+		return fmt.Errorf("failed to write known files: %w", err)
+	}
+	return nil
+}
+
+// snapshotModsDirectory returns the size and modification time of every file in the Mods directory.
+// Only the directories are listed, no file is read
+func snapshotModsDirectory(d disk.Disk, modsDirectory string) (map[string]fileState, error) {
+	exists, err := d.Exists(modsDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if Mods directory exists: %w", err)
+	}
+	if !exists {
+		return make(map[string]fileState), nil
+	}
+
+	return walkDirectory(d, modsDirectory, "")
+}
+
+func walkDirectory(d disk.Disk, root string, relativePath string) (map[string]fileState, error) {
+	files := make(map[string]fileState)
+
+	entries, err := d.ReadDir(filepath.Join(root, relativePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", filepath.Join(root, relativePath), err)
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(relativePath, entry.Name())
+		if entry.IsDir() {
+			subFiles, err := walkDirectory(d, root, entryPath)
+			if err != nil {
+				return nil, err
+			}
+			maps.Copy(files, subFiles)
+			continue
+		}
+		files[entryPath] = entryState(entry)
+	}
+
+	return files, nil
+}
+
+// entryState returns the size and modification time of the entry, if the disk's listing has them.
+// Local and SFTP listings have them. FTP entries do not expose them,
+// so on FTP installs only created and deleted files are detected
+func entryState(entry disk.Entry) fileState {
+	switch e := entry.(type) {
+	case interface{ Info() (fs.FileInfo, error) }:
+		info, err := e.Info()
+		if err != nil {
+			return fileState{}
+		}
+		return fileState{Size: info.Size(), ModTime: info.ModTime()}
+	case interface {
+		Size() int64
+		ModTime() time.Time
+	}:
+		return fileState{Size: e.Size(), ModTime: e.ModTime()}
+	}
+	return fileState{}
+}
+
+// diffModsDirectory returns the changed files, sorted by path.
+// A file is considered replaced when its size or modification time changed
+func diffModsDirectory(before map[string]fileState, after map[string]fileState) []FileChange {
+	changes := []FileChange{}
+
+	for path, newState := range after {
+		oldState, existed := before[path]
+		if !existed {
+			changes = append(changes, FileChange{
+				Path:    path,
+				Type:    FileChangeCreated,
+				NewSize: newState.Size,
+			})
+			continue
+		}
+		if oldState.Size != newState.Size || !oldState.ModTime.Equal(newState.ModTime) {
+			changes = append(changes, FileChange{
+				Path:    path,
+				Type:    FileChangeReplaced,
+				OldSize: oldState.Size,
+				NewSize: newState.Size,
+			})
+		}
+	}
+
+	for path, oldState := range before {
+		if _, exists := after[path]; !exists {
+			changes = append(changes, FileChange{
+				Path:    path,
+				Type:    FileChangeDeleted,
+				OldSize: oldState.Size,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+func readApplyHistory(installPath string) ([]ApplyRecord, error) {
+	data, err := os.ReadFile(applyHistoryPath(installPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ApplyRecord{}, nil
+		}
+		return nil, fmt.Errorf("failed to read apply history: %w", err)
+	}
+
+	var history []ApplyRecord
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal apply history: %w", err)
+	}
+	return history, nil
+}
+
+func saveApplyRecord(record ApplyRecord) error {
+	applyHistoryMutex.Lock()
+	defer applyHistoryMutex.Unlock()
+
+	history, err := readApplyHistory(record.Install)
+	if err != nil {
+		// A broken history should not prevent recording new applies
+		slog.Warn("discarding apply history", slog.String("install", record.Install), slog.Any("error", err))
+		history = []ApplyRecord{}
+	}
+
+	history = append(history, record)
+	if len(history) > applyHistorySize {
+		history = history[len(history)-applyHistorySize:]
+	}
+
+	data, err := utils.JSONMarshal(history, 2)
+	if err != nil {
+		return fmt.Errorf("failed to marshal apply history: %w", err)
+	}
+
+	historyPath := applyHistoryPath(record.Install)
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create apply history directory: %w", err)
+	}
+	if err := os.WriteFile(historyPath, data, 0o755); err != nil {
+		return fmt.Errorf("failed to write apply history: %w", err)
+	}
+
+	return nil
+}
+
+// GetApplyHistory returns the recorded applies of the install, most recent first
+func (f *ficsitCLI) GetApplyHistory(installPath string) ([]ApplyRecord, error) {
+	applyHistoryMutex.Lock()
+	defer applyHistoryMutex.Unlock()
+
+	history, err := readApplyHistory(installPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
@@ -0,0 +1,149 @@
+package ficsitcli
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli/disk"
+	"github.com/spf13/viper"
+)
+
+func TestDiffModsDirectory(t *testing.T) {
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	laterModTime := modTime.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		before map[string]fileState
+		after  map[string]fileState
+		want   []FileChange
+	}{
+		{
+			name:   "no changes",
+			before: map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			after:  map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			want:   []FileChange{},
+		},
+		{
+			name:   "created",
+			before: map[string]fileState{},
+			after:  map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			want:   []FileChange{{Path: "SML/SML.uplugin", Type: FileChangeCreated, NewSize: 10}},
+		},
+		{
+			name:   "deleted",
+			before: map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			after:  map[string]fileState{},
+			want:   []FileChange{{Path: "SML/SML.uplugin", Type: FileChangeDeleted, OldSize: 10}},
+		},
+		{
+			name:   "replaced with a different size",
+			before: map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			after:  map[string]fileState{"SML/SML.uplugin": {Size: 12, ModTime: modTime}},
+			want:   []FileChange{{Path: "SML/SML.uplugin", Type: FileChangeReplaced, OldSize: 10, NewSize: 12}},
+		},
+		{
+			name:   "replaced with the same size",
+			before: map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			after:  map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: laterModTime}},
+			want:   []FileChange{{Path: "SML/SML.uplugin", Type: FileChangeReplaced, OldSize: 10, NewSize: 10}},
+		},
+		{
+			name:   "same time in another location",
+			before: map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime}},
+			after:  map[string]fileState{"SML/SML.uplugin": {Size: 10, ModTime: modTime.In(time.FixedZone("UTC+2", 2*60*60))}},
+			want:   []FileChange{},
+		},
+		{
+			name: "sorted by path",
+			before: map[string]fileState{
+				"b.pak": {Size: 1, ModTime: modTime},
+				"c.pak": {Size: 1, ModTime: modTime},
+			},
+			after: map[string]fileState{
+				"a.pak": {Size: 1, ModTime: modTime},
+				"c.pak": {Size: 2, ModTime: modTime},
+			},
+			want: []FileChange{
+				{Path: "a.pak", Type: FileChangeCreated, NewSize: 1},
+				{Path: "b.pak", Type: FileChangeDeleted, OldSize: 1},
+				{Path: "c.pak", Type: FileChangeReplaced, OldSize: 1, NewSize: 2},
+			},
+		},
+		{
+			// FTP listings have no size or modification time
+			name:   "without file info",
+			before: map[string]fileState{"a.pak": {}, "b.pak": {}},
+			after:  map[string]fileState{"a.pak": {}, "c.pak": {}},
+			want: []FileChange{
+				{Path: "b.pak", Type: FileChangeDeleted},
+				{Path: "c.pak", Type: FileChangeCreated},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffModsDirectory(tt.before, tt.after); !slices.Equal(got, tt.want) {
+				t.Errorf("diffModsDirectory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashChangesKeepsHashesAcrossApplies(t *testing.T) {
+	viper.Set("smm-local-dir", t.TempDir())
+	t.Cleanup(func() { viper.Set("smm-local-dir", "") })
+
+	installPath := t.TempDir()
+	modsDirectory := filepath.Join(installPath, "FactoryGame", "Mods")
+	if err := os.MkdirAll(modsDirectory, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	d, err := disk.FromPath(installPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apply := func(content string) []FileChange {
+		t.Helper()
+		before, err := snapshotModsDirectory(d, modsDirectory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content == "" {
+			err = os.Remove(filepath.Join(modsDirectory, "a.pak"))
+		} else {
+			err = os.WriteFile(filepath.Join(modsDirectory, "a.pak"), []byte(content), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := snapshotModsDirectory(d, modsDirectory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		changes := diffModsDirectory(before, after)
+		hashChanges(slog.Default(), d, installPath, modsDirectory, changes, before, after)
+		return changes
+	}
+
+	created := apply("first")
+	if len(created) != 1 || created[0].NewHash == "" || created[0].OldHash != "" {
+		t.Fatalf("created = %v, want one change with only a new hash", created)
+	}
+
+	replaced := apply("second version")
+	if len(replaced) != 1 || replaced[0].OldHash != created[0].NewHash || replaced[0].NewHash == created[0].NewHash {
+		t.Fatalf("replaced = %v, want the old hash to be the hash recorded when it was created", replaced)
+	}
+
+	deleted := apply("")
+	if len(deleted) != 1 || deleted[0].OldHash != replaced[0].NewHash || deleted[0].NewHash != "" {
+		t.Fatalf("deleted = %v, want the old hash to be the hash recorded when it was replaced", deleted)
+	}
+}
@@ -0,0 +1,236 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	"github.com/spf13/viper"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	appCommon "github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
+)
+
+type AutoUpdateInstallResult struct {
+	Install string   `json:"install"`
+	Profile string   `json:"profile"`
+	Updates []Update `json:"updates"`
+	// Backup is the path of the lockfile saved before updating
+	Backup string `json:"backup,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type AutoUpdateSummary struct {
+	Started  time.Time                 `json:"started"`
+	Finished time.Time                 `json:"finished"`
+	Installs []AutoUpdateInstallResult `json:"installs"`
+}
+
+const autoUpdateTickInterval = time.Minute
+
+// StartAutoUpdateScheduler runs the scheduled updates of the opted-in installs.
+// When an update is due while the game is running or another operation is in progress,
+// the install is updated on the first tick after that is no longer the case.
+// Updates missed while the computer was asleep are run once, not once per missed time
+func (f *ficsitCLI) StartAutoUpdateScheduler() {
+	go func() {
+		// The monotonic clock does not advance while the computer is asleep, so only the wall clock is compared
+		lastScheduled := time.Now().Round(0)
+		pendingInstalls := make(map[string]bool)
+
+		ticker := time.NewTicker(autoUpdateTickInterval)
+		for now := range ticker.C {
+			schedule := settings.Settings.AutoUpdateSchedule
+			if !schedule.Enabled {
+				clear(pendingInstalls)
+				lastScheduled = now.Round(0)
+				continue
+			}
+
+			// The next update is computed from the last one on every tick, so schedule changes apply right away
+			nextUpdate := nextAutoUpdate(schedule, lastScheduled)
+			if !nextUpdate.IsZero() && !now.Before(nextUpdate) {
+				lastScheduled = now.Round(0)
+				for _, install := range schedule.Installs {
+					pendingInstalls[install] = true
+				}
+			}
+
+			var installs []string
+			for install := range pendingInstalls {
+				if !slices.Contains(schedule.Installs, install) {
+					// Opted out since it was scheduled
+					delete(pendingInstalls, install)
+					continue
+				}
+				if !f.isGameRunningOn(install) {
+					installs = append(installs, install)
+				}
+			}
+			if len(installs) == 0 {
+				continue
+			}
+			sort.Strings(installs)
+
+			summary, err := f.AutoUpdate(installs)
+			if err != nil {
+				slog.Warn("scheduled update postponed", slog.Any("error", err))
+				continue
+			}
+			for _, install := range installs {
+				delete(pendingInstalls, install)
+			}
+			if summary != nil && appCommon.AppContext != nil {
+				wailsRuntime.EventsEmit(appCommon.AppContext, "autoUpdateSummary", summary)
+			}
+		}
+	}()
+}
+
+// nextAutoUpdate returns the first time after lastScheduled an update is due at, or the zero time if the schedule has none
+func nextAutoUpdate(schedule settings.AutoUpdateSchedule, lastScheduled time.Time) time.Time {
+	var next time.Time
+	if schedule.IntervalHours > 0 {
+		next = lastScheduled.Add(time.Duration(schedule.IntervalHours) * time.Hour)
+	}
+	for _, scheduleTime := range schedule.Times {
+		timeOfDay, err := time.Parse(settings.AutoUpdateTimeFormat, scheduleTime)
+		if err != nil {
+			continue
+		}
+		year, month, day := lastScheduled.Date()
+		due := time.Date(year, month, day, timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, lastScheduled.Location())
+		if !due.After(lastScheduled) {
+			due = time.Date(year, month, day+1, timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, lastScheduled.Location())
+		}
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next
+}
+
+// AutoUpdate backs up the lockfile of the installs, and applies their updates, excluding ignored ones.
+// Returns nil if there were no updates to apply
+func (f *ficsitCLI) AutoUpdate(installs []string) (*AutoUpdateSummary, error) {
+	summary := &AutoUpdateSummary{
+		Started:  time.Now().UTC(),
+		Installs: []AutoUpdateInstallResult{},
+	}
+
+	var scope actionScope
+	for _, installPath := range installs {
+		if installation := f.GetInstallation(installPath); installation != nil {
+			scope = scope.with(installProfileScope(installation))
+		}
+	}
+	if len(scope.installs) == 0 {
+		// None of the installs exist anymore
+		return nil, nil
+	}
+
+	err := f.action(ActionAutoUpdate, noItem, scope, func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		defer close(taskChannel)
+
+		settings.Settings.CleanupIgnoreRules()
+
+		updatedInstalls := make(map[string]bool, len(installs))
+		for _, installPath := range installs {
+			updatedInstalls[installPath] = true
+		}
+
+		for _, installPath := range installs {
+			installLogger := l.With(slog.String("install", installPath))
+
+			installation := f.GetInstallation(installPath)
+			if installation == nil || installation.Vanilla || !f.isValidInstall(installPath) {
+				installLogger.Info("skipping install that cannot be updated")
+				continue
+			}
+
+			result := AutoUpdateInstallResult{
+				Install: installPath,
+				Profile: installation.Profile,
+				Updates: []Update{},
+			}
+
+			updates, err := f.checkInstallForUpdates(installLogger, installation)
+			if err != nil {
+				result.Error = err.Error()
+				summary.Installs = append(summary.Installs, result)
+				continue
+			}
+			for _, update := range updates {
+				if !update.Ignored {
+					result.Updates = append(result.Updates, update)
+				}
+			}
+			if len(result.Updates) == 0 {
+				continue
+			}
+
+			result.Backup, err = f.backupLockfile(installation)
+			if err != nil {
+				installLogger.Error("failed to back up lockfile", slog.Any("error", err))
+				result.Error = err.Error()
+				summary.Installs = append(summary.Installs, result)
+				continue
+			}
+
+			err = f.updateInstall(installLogger, installation, result.Updates, updatedInstalls, taskChannel)
+			if err != nil {
+				installLogger.Error("failed to update install", slog.Any("error", err))
+				result.Error = err.Error()
+			}
+			summary.Installs = append(summary.Installs, result)
+		}
+
+		f.EmitModsChange()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Finished = time.Now().UTC()
+
+	if len(summary.Installs) == 0 {
+		return nil, nil
+	}
+	return summary, nil
+}
+
+// backupLockfile saves the current lockfile of the install, so the previous versions can be restored
+func (f *ficsitCLI) backupLockfile(installation *cli.Installation) (string, error) {
+	lockfile, err := installation.LockFile(f.ficsitCli)
+	if err != nil {
+		return "", fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	if lockfile == nil {
+		return "", nil
+	}
+
+	data, err := utils.JSONMarshal(lockfile, 2)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+
+	backupDir := filepath.Join(viper.GetString("smm-local-dir"), "lockfile-backups")
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	backupPath := filepath.Join(backupDir, fmt.Sprintf("%s_%s.json", installPathHash(installation.Path), time.Now().UTC().Format("20060102T150405Z")))
+	if err := os.WriteFile(backupPath, data, 0o755); err != nil {
+		return "", fmt.Errorf("failed to write lockfile backup: %w", err)
+	}
+
+	return backupPath, nil
+}
@@ -0,0 +1,62 @@
+package ficsitcli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+func TestNextAutoUpdate(t *testing.T) {
+	lastScheduled := time.Date(2024, 1, 1, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		schedule settings.AutoUpdateSchedule
+		want     time.Time
+	}{
+		{
+			name:     "no schedule",
+			schedule: settings.AutoUpdateSchedule{},
+			want:     time.Time{},
+		},
+		{
+			name:     "interval",
+			schedule: settings.AutoUpdateSchedule{IntervalHours: 6},
+			want:     time.Date(2024, 1, 1, 16, 30, 0, 0, time.Local),
+		},
+		{
+			name:     "later today",
+			schedule: settings.AutoUpdateSchedule{Times: []string{"04:00", "18:00"}},
+			want:     time.Date(2024, 1, 1, 18, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "tomorrow",
+			schedule: settings.AutoUpdateSchedule{Times: []string{"04:00"}},
+			want:     time.Date(2024, 1, 2, 4, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "same time tomorrow",
+			schedule: settings.AutoUpdateSchedule{Times: []string{"10:30"}},
+			want:     time.Date(2024, 1, 2, 10, 30, 0, 0, time.Local),
+		},
+		{
+			name:     "earliest of interval and times",
+			schedule: settings.AutoUpdateSchedule{IntervalHours: 24, Times: []string{"12:00"}},
+			want:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "invalid time",
+			schedule: settings.AutoUpdateSchedule{Times: []string{"noon"}},
+			want:     time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextAutoUpdate(tt.schedule, lastScheduled); !got.Equal(tt.want) {
+				t.Errorf("nextAutoUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
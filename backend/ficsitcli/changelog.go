@@ -0,0 +1,357 @@
+package ficsitcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+	"github.com/spf13/viper"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
+)
+
+type VersionChangelog struct {
+	Version   string `json:"version"`
+	Changelog string `json:"changelog"`
+}
+
+type DependencyChangeType string
+
+const (
+	DependencyChangeAdded             DependencyChangeType = "added"
+	DependencyChangeRemoved           DependencyChangeType = "removed"
+	DependencyChangeConstraintChanged DependencyChangeType = "constraintChanged"
+)
+
+var AllDependencyChangeTypes = []struct {
+	Value  DependencyChangeType
+	TSName string
+}{
+	{DependencyChangeAdded, "ADDED"},
+	{DependencyChangeRemoved, "REMOVED"},
+	{DependencyChangeConstraintChanged, "CONSTRAINT_CHANGED"},
+}
+
+type DependencyChange struct {
+	Dependency string               `json:"dependency"`
+	Type       DependencyChangeType `json:"type"`
+	From       string               `json:"from,omitempty"`
+	To         string               `json:"to,omitempty"`
+	// NewlyInstalled is set for dependencies that were not installed before the update
+	NewlyInstalled bool `json:"newlyInstalled"`
+}
+
+const getChangelogsQuery = `query GetChangelogs($modReference: ModReference!, $limit: Int!, $offset: Int!) {
+  getModByReference(modReference: $modReference) {
+    versions(filter: { limit: $limit, offset: $offset, order_by: created_at, order: desc }) {
+      version
+      changelog
+    }
+  }
+}`
+
+// changelogsPageSize is the most versions the API returns per request
+const changelogsPageSize = 100
+
+type getChangelogsResponse struct {
+	GetModByReference *struct {
+		Versions []VersionChangelog `json:"versions"`
+	} `json:"getModByReference"`
+}
+
+// Changelogs never change once a version is published, so every fetched changelog is kept,
+// and used when offline or when the API is unreachable
+
+func changelogCachePath(modReference string) string {
+	return filepath.Join(viper.GetString("cache-dir"), "changelogs", modReference+".json")
+}
+
+func readCachedChangelogs(modReference string) (map[string]string, error) {
+	changelogs := make(map[string]string)
+	data, err := os.ReadFile(changelogCachePath(modReference))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return changelogs, nil
+		}
+		return nil, fmt.Errorf("failed to read cached changelogs: %w", err)
+	}
+	if err := json.Unmarshal(data, &changelogs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached changelogs: %w", err)
+	}
+	return changelogs, nil
+}
+
+func writeCachedChangelogs(modReference string, changelogs map[string]string) error {
+	cachePath := changelogCachePath(modReference)
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
+		return fmt.Errorf("failed to create changelog cache directory: %w", err)
+	}
+	data, err := utils.JSONMarshal(changelogs, 2)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changelogs: %w", err)
+	}
+	if err := os.WriteFile(cachePath, data, 0o755); err != nil {
+		return fmt.Errorf("failed to write cached changelogs: %w", err)
+	}
+	return nil
+}
+
+// fetchChangelogs fetches the changelogs of the mod newest first, a page at a time,
+// until a page contains a version not newer than oldest, or there are no more versions
+func (f *ficsitCLI) fetchChangelogs(modReference string, oldest semver.Version) (map[string]string, error) {
+	changelogs := make(map[string]string)
+	for offset := 0; ; offset += changelogsPageSize {
+		response := getChangelogsResponse{}
+		err := f.ficsitCli.APIClient.MakeRequest(context.TODO(), &graphql.Request{
+			OpName: "GetChangelogs",
+			Query:  getChangelogsQuery,
+			Variables: map[string]interface{}{
+				"modReference": modReference,
+				"limit":        changelogsPageSize,
+				"offset":       offset,
+			},
+		}, &graphql.Response{Data: &response})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch changelogs: %w", err)
+		}
+		if response.GetModByReference == nil {
+			return nil, fmt.Errorf("mod not found: %s", modReference)
+		}
+
+		versions := response.GetModByReference.Versions
+		reachedOldest := false
+		for _, version := range versions {
+			changelogs[version.Version] = version.Changelog
+			parsedVersion, err := semver.NewVersion(version.Version)
+			if err == nil && parsedVersion.Compare(oldest) <= 0 {
+				reachedOldest = true
+			}
+		}
+		if reachedOldest || len(versions) < changelogsPageSize {
+			return changelogs, nil
+		}
+	}
+}
+
+// getChangelogs returns the changelogs of a mod by version, at least down to the oldest version,
+// from the API unless offline, falling back to the cache
+func (f *ficsitCLI) getChangelogs(l *slog.Logger, modReference string, oldest semver.Version) map[string]string {
+	changelogs, err := readCachedChangelogs(modReference)
+	if err != nil {
+		l.Warn("failed to read cached changelogs", slog.String("mod", modReference), slog.Any("error", err))
+		changelogs = make(map[string]string)
+	}
+
+	if f.ficsitCli.Provider.IsOffline() {
+		return changelogs
+	}
+
+	fetched, err := f.fetchChangelogs(modReference, oldest)
+	if err != nil {
+		l.Warn("failed to fetch changelogs, using cache", slog.String("mod", modReference), slog.Any("error", err))
+		return changelogs
+	}
+
+	for version, changelog := range fetched {
+		changelogs[version] = changelog
+	}
+	err = writeCachedChangelogs(modReference, changelogs)
+	if err != nil {
+		l.Warn("failed to cache changelogs", slog.String("mod", modReference), slog.Any("error", err))
+	}
+
+	return changelogs
+}
+
+// UpdateDetails is what an update changes. Update checks attach them to every update,
+// GetUpdateDetails returns them for any version range
+type UpdateDetails struct {
+	// Changelogs of the versions after the current version, up to and including the new version, newest first
+	Changelogs        []VersionChangelog `json:"changelogs"`
+	DependencyChanges []DependencyChange `json:"dependencyChanges"`
+}
+
+// GetUpdateDetails returns the changelogs and dependency changes of an update of the selected install
+func (f *ficsitCLI) GetUpdateDetails(update Update) (*UpdateDetails, error) {
+	l := slog.With(slog.String("task", "getUpdateDetails"), slog.String("mod", update.Item))
+
+	lockfile, err := f.GetSelectedInstallLockfile()
+	if err != nil {
+		l.Warn("failed to read lockfile", slog.Any("error", err))
+	}
+
+	dependencyChanges, err := f.updateDependencyChanges(update, installedMods(lockfile))
+	if err != nil {
+		l.Error("failed to get dependency changes", slog.Any("error", err))
+		return nil, err
+	}
+
+	return &UpdateDetails{
+		Changelogs:        f.updateChangelogs(l, update),
+		DependencyChanges: dependencyChanges,
+	}, nil
+}
+
+// addUpdateDetails attaches the changelogs and dependency changes to the updates of an install with the given lockfile.
+// Details that cannot be fetched are left empty, so they do not fail the update check
+func (f *ficsitCLI) addUpdateDetails(l *slog.Logger, updates []Update, lockfile *resolver.LockFile) {
+	installed := installedMods(lockfile)
+
+	var wg sync.WaitGroup
+	// Limits the concurrent requests to the API
+	semaphore := make(chan struct{}, 4)
+	for i := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			update := &updates[i]
+			update.Changelogs = f.updateChangelogs(l, *update)
+			dependencyChanges, err := f.updateDependencyChanges(*update, installed)
+			if err != nil {
+				l.Warn("failed to get dependency changes", slog.String("mod", update.Item), slog.Any("error", err))
+				dependencyChanges = []DependencyChange{}
+			}
+			update.DependencyChanges = dependencyChanges
+		}()
+	}
+	wg.Wait()
+}
+
+func installedMods(lockfile *resolver.LockFile) map[string]bool {
+	installed := make(map[string]bool)
+	if lockfile != nil {
+		for modReference := range lockfile.Mods {
+			installed[modReference] = true
+		}
+	}
+	return installed
+}
+
+// updateChangelogs returns the changelogs of the versions after the update's current version,
+// up to and including the new version, newest first
+func (f *ficsitCLI) updateChangelogs(l *slog.Logger, update Update) []VersionChangelog {
+	result := []VersionChangelog{}
+
+	currentVersion, err := semver.NewVersion(update.CurrentVersion)
+	if err != nil {
+		l.Warn("failed to parse current version", slog.String("mod", update.Item), slog.Any("error", err))
+		return result
+	}
+	newVersion, err := semver.NewVersion(update.NewVersion)
+	if err != nil {
+		l.Warn("failed to parse new version", slog.String("mod", update.Item), slog.Any("error", err))
+		return result
+	}
+
+	versions := make(map[string]semver.Version)
+	for version, changelog := range f.getChangelogs(l, update.Item, currentVersion) {
+		parsedVersion, err := semver.NewVersion(version)
+		if err != nil {
+			continue
+		}
+		if parsedVersion.Compare(currentVersion) <= 0 || parsedVersion.Compare(newVersion) > 0 {
+			continue
+		}
+		versions[version] = parsedVersion
+		result = append(result, VersionChangelog{
+			Version:   version,
+			Changelog: changelog,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return versions[result[i].Version].Compare(versions[result[j].Version]) > 0
+	})
+
+	return result
+}
+
+// updateDependencyChanges compares the dependencies the provider lists for the mod's current and new versions.
+// Lockfiles do not store dependencies, so they cannot be used for this
+func (f *ficsitCLI) updateDependencyChanges(update Update, installed map[string]bool) ([]DependencyChange, error) {
+	modVersions, err := f.ficsitCli.Provider.ModVersionsWithDependencies(context.TODO(), update.Item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions of %s: %w", update.Item, err)
+	}
+
+	var currentDependencies, newDependencies []resolver.Dependency
+	foundCurrent, foundNew := false, false
+	for _, modVersion := range modVersions {
+		switch modVersion.Version {
+		case update.CurrentVersion:
+			currentDependencies = modVersion.Dependencies
+			foundCurrent = true
+		case update.NewVersion:
+			newDependencies = modVersion.Dependencies
+			foundNew = true
+		}
+	}
+	if !foundCurrent {
+		return nil, fmt.Errorf("version %s of %s not found", update.CurrentVersion, update.Item)
+	}
+	if !foundNew {
+		return nil, fmt.Errorf("version %s of %s not found", update.NewVersion, update.Item)
+	}
+
+	return diffDependencies(currentDependencies, newDependencies, installed), nil
+}
+
+// diffDependencies returns the changes between two versions' dependencies, sorted by dependency
+func diffDependencies(currentDependencies []resolver.Dependency, newDependencies []resolver.Dependency, installed map[string]bool) []DependencyChange {
+	changes := []DependencyChange{}
+
+	current := make(map[string]string, len(currentDependencies))
+	for _, dependency := range currentDependencies {
+		current[dependency.ModID] = dependency.Condition
+	}
+	updated := make(map[string]string, len(newDependencies))
+	for _, dependency := range newDependencies {
+		updated[dependency.ModID] = dependency.Condition
+	}
+
+	for dependency, newConstraint := range updated {
+		currentConstraint, ok := current[dependency]
+		switch {
+		case !ok:
+			changes = append(changes, DependencyChange{
+				Dependency:     dependency,
+				Type:           DependencyChangeAdded,
+				To:             newConstraint,
+				NewlyInstalled: !installed[dependency],
+			})
+		case currentConstraint != newConstraint:
+			changes = append(changes, DependencyChange{
+				Dependency: dependency,
+				Type:       DependencyChangeConstraintChanged,
+				From:       currentConstraint,
+				To:         newConstraint,
+			})
+		}
+	}
+	for dependency, currentConstraint := range current {
+		if _, ok := updated[dependency]; !ok {
+			changes = append(changes, DependencyChange{
+				Dependency: dependency,
+				Type:       DependencyChangeRemoved,
+				From:       currentConstraint,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Dependency < changes[j].Dependency
+	})
+
+	return changes
+}
@@ -0,0 +1,191 @@
+package ficsitcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+)
+
+// GameVersionCompatibility is the result of resolving a profile against a game version
+type GameVersionCompatibility struct {
+	GameVersion int `json:"gameVersion"`
+	// Installs and Branches are the known installs with this game version, if any
+	Installs []string            `json:"installs"`
+	Branches []common.GameBranch `json:"branches"`
+	Resolves bool                `json:"resolves"`
+	Error    string              `json:"error,omitempty"`
+	// BlockingMods are the enabled mods with no version compatible with the game version
+	BlockingMods []string `json:"blockingMods"`
+	// Updates are the changes from the profile's current lockfile needed for this game version
+	Updates []Update `json:"updates"`
+}
+
+// CheckProfileCompatibility resolves a profile against the given game version
+func (f *ficsitCLI) CheckProfileCompatibility(profileName string, gameVersion int) (*GameVersionCompatibility, error) {
+	l := slog.With(slog.String("task", "checkProfileCompatibility"), slog.String("profile", profileName), slog.Int("gameVersion", gameVersion))
+
+	profile := f.copyProfile(profileName)
+	if profile == nil {
+		return nil, fmt.Errorf("profile not found: %s", profileName)
+	}
+
+	compatibility := f.checkProfileCompatibility(l, profile, f.profileLockfile(l, profileName), gameVersion)
+	return &compatibility, nil
+}
+
+// CheckProfileCompatibilityWithInstalls resolves a profile against the game versions of all known installs,
+// regardless of the profile they use, including other branches
+func (f *ficsitCLI) CheckProfileCompatibilityWithInstalls(profileName string) ([]GameVersionCompatibility, error) {
+	l := slog.With(slog.String("task", "checkProfileCompatibilityWithInstalls"), slog.String("profile", profileName))
+
+	profile := f.copyProfile(profileName)
+	if profile == nil {
+		return nil, fmt.Errorf("profile not found: %s", profileName)
+	}
+
+	installsByVersion := make(map[int][]*common.Installation)
+	f.installationMetadata.Range(func(_ string, metadata installationMetadata) bool {
+		if metadata.Info != nil && metadata.Info.Version != 0 {
+			installsByVersion[metadata.Info.Version] = append(installsByVersion[metadata.Info.Version], metadata.Info)
+		}
+		return true
+	})
+
+	lockfile := f.profileLockfile(l, profileName)
+
+	results := make([]GameVersionCompatibility, 0, len(installsByVersion))
+	for gameVersion, installs := range installsByVersion {
+		compatibility := f.checkProfileCompatibility(l, profile, lockfile, gameVersion)
+		for _, install := range installs {
+			compatibility.Installs = append(compatibility.Installs, install.Path)
+			if !slices.Contains(compatibility.Branches, install.Branch) {
+				compatibility.Branches = append(compatibility.Branches, install.Branch)
+			}
+		}
+		sort.Strings(compatibility.Installs)
+		results = append(results, compatibility)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].GameVersion < results[j].GameVersion
+	})
+
+	return results, nil
+}
+
+// profileLockfile returns the lockfile of an install using the profile, preferring the selected install
+func (f *ficsitCLI) profileLockfile(l *slog.Logger, profileName string) *resolver.LockFile {
+	for _, installation := range f.profileInstalls(profileName) {
+		lockfile, err := installation.LockFile(f.ficsitCli)
+		if err != nil {
+			l.Warn("failed to read lockfile", slog.String("install", installation.Path), slog.Any("error", err))
+			continue
+		}
+		if lockfile != nil {
+			return lockfile
+		}
+	}
+	return nil
+}
+
+// checkProfileCompatibility resolves the profile, which must be a copy made with copyProfile, since resolving reads its mods
+func (f *ficsitCLI) checkProfileCompatibility(l *slog.Logger, profile *cli.Profile, lockfile *resolver.LockFile, gameVersion int) GameVersionCompatibility {
+	result := GameVersionCompatibility{
+		GameVersion:  gameVersion,
+		Installs:     []string{},
+		Branches:     []common.GameBranch{},
+		BlockingMods: []string{},
+		Updates:      []Update{},
+	}
+
+	res := resolver.NewDependencyResolver(f.ficsitCli.Provider)
+	newLockfile, err := profile.Resolve(res, lockfile, gameVersion)
+	if err != nil {
+		l.Info("profile does not resolve", slog.Int("gameVersion", gameVersion), slog.Any("error", err))
+		var solvingError resolver.DependencyResolverError
+		if errors.As(err, &solvingError) {
+			result.Error = solvingError.Error()
+		} else {
+			result.Error = err.Error()
+		}
+		result.BlockingMods = f.findBlockingMods(l, profile, gameVersion)
+		return result
+	}
+
+	result.Resolves = true
+	if lockfile != nil {
+		for modReference, newLockedMod := range newLockfile.Mods {
+			if prevLockedMod, ok := lockfile.Mods[modReference]; ok && prevLockedMod.Version != newLockedMod.Version {
+				result.Updates = append(result.Updates, Update{
+					Item:           modReference,
+					CurrentVersion: prevLockedMod.Version,
+					NewVersion:     newLockedMod.Version,
+				})
+			}
+		}
+		sort.Slice(result.Updates, func(i, j int) bool {
+			return result.Updates[i].Item < result.Updates[j].Item
+		})
+	}
+
+	return result
+}
+
+// findBlockingMods returns the enabled mods of the profile that have no version, matching the profile's constraint,
+// that supports the game version. A profile can also fail to resolve because of conflicting dependencies,
+// in which case no mod is blocking by itself
+func (f *ficsitCLI) findBlockingMods(l *slog.Logger, profile *cli.Profile, gameVersion int) []string {
+	blocking := []string{}
+
+	gameVersionSemver, err := semver.NewVersion(fmt.Sprintf("%d", gameVersion))
+	if err != nil {
+		l.Warn("failed to parse game version", slog.Int("gameVersion", gameVersion), slog.Any("error", err))
+		return blocking
+	}
+
+	for modReference, profileMod := range profile.Mods {
+		if !profileMod.Enabled {
+			continue
+		}
+		constraint, err := semver.NewConstraint(profileMod.Version)
+		if err != nil {
+			l.Warn("failed to parse mod constraint", slog.String("mod", modReference), slog.Any("error", err))
+			continue
+		}
+		modVersions, err := f.ficsitCli.Provider.ModVersionsWithDependencies(context.TODO(), modReference)
+		if err != nil {
+			l.Warn("failed to get mod versions", slog.String("mod", modReference), slog.Any("error", err))
+			continue
+		}
+
+		compatible := slices.ContainsFunc(modVersions, func(modVersion resolver.ModVersion) bool {
+			version, err := semver.NewVersion(modVersion.Version)
+			if err != nil || !constraint.Contains(version) {
+				return false
+			}
+			if modVersion.GameVersion == "" {
+				return true
+			}
+			gameVersionConstraint, err := semver.NewConstraint(modVersion.GameVersion)
+			if err != nil {
+				return false
+			}
+			return gameVersionConstraint.Contains(gameVersionSemver)
+		})
+		if !compatible {
+			blocking = append(blocking, modReference)
+		}
+	}
+
+	sort.Strings(blocking)
+	return blocking
+}
@@ -0,0 +1,293 @@
+package ficsitcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	"github.com/satisfactorymodding/ficsit-cli/cli/cache"
+	"github.com/satisfactorymodding/ficsit-cli/cli/disk"
+	psUtilDisk "github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
+)
+
+type DiagnosticSeverity string
+
+const (
+	DiagnosticSeverityOK      DiagnosticSeverity = "ok"
+	DiagnosticSeverityWarning DiagnosticSeverity = "warning"
+	DiagnosticSeverityError   DiagnosticSeverity = "error"
+)
+
+var AllDiagnosticSeverities = []struct {
+	Value  DiagnosticSeverity
+	TSName string
+}{
+	{DiagnosticSeverityOK, "OK"},
+	{DiagnosticSeverityWarning, "WARNING"},
+	{DiagnosticSeverityError, "ERROR"},
+}
+
+// minFreeDiskSpace is the free space below which installing mods may fail
+const minFreeDiskSpace = 1024 * 1024 * 1024
+
+// DiagnosticFinding is the result of one check of an install
+type DiagnosticFinding struct {
+	Check    string             `json:"check"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Message  string             `json:"message"`
+	Fix      string             `json:"fix,omitempty"`
+}
+
+type InstallationDiagnostics struct {
+	Install  string              `json:"install"`
+	Time     time.Time           `json:"time"`
+	Findings []DiagnosticFinding `json:"findings"`
+}
+
+// Severity is the highest severity of the findings
+func (d *InstallationDiagnostics) Severity() DiagnosticSeverity {
+	severity := DiagnosticSeverityOK
+	for _, finding := range d.Findings {
+		if finding.Severity == DiagnosticSeverityError {
+			return DiagnosticSeverityError
+		}
+		if finding.Severity == DiagnosticSeverityWarning {
+			severity = DiagnosticSeverityWarning
+		}
+	}
+	return severity
+}
+
+func (d *InstallationDiagnostics) add(check string, severity DiagnosticSeverity, message string, fix string) {
+	d.Findings = append(d.Findings, DiagnosticFinding{
+		Check:    check,
+		Severity: severity,
+		Message:  message,
+		Fix:      fix,
+	})
+}
+
+// DiagnoseInstallation checks the install for the common causes of failed applies and broken games.
+// Every check adds a finding, so the report also shows what was found to be fine.
+// Only with probeWrite does it create the Mods directory and write a test file to check write access,
+// otherwise the install is only read
+func (f *ficsitCLI) DiagnoseInstallation(path string, probeWrite bool) (*InstallationDiagnostics, error) {
+	installation := f.GetInstallation(path)
+	if installation == nil {
+		return nil, fmt.Errorf("installation not found")
+	}
+
+	l := slog.With(slog.String("task", "diagnoseInstallation"), slog.String("install", path))
+
+	diagnostics := &InstallationDiagnostics{
+		Install:  path,
+		Time:     time.Now().UTC(),
+		Findings: []DiagnosticFinding{},
+	}
+
+	metadata, _ := f.installationMetadata.Load(path)
+	isRemote := !isLocalPath(path)
+
+	d, err := installation.GetDisk()
+	if err != nil {
+		if isRemote {
+			diagnostics.add("connection", DiagnosticSeverityError, fmt.Sprintf("Failed to connect to the server: %s", redactError(installation.Path, err)), "Check that the server is online and that the address, port, username and password are correct")
+		} else {
+			diagnostics.add("disk", DiagnosticSeverityError, fmt.Sprintf("Failed to access the install: %s", redactError(installation.Path, err)), "Check that the install folder still exists")
+		}
+		return diagnostics, nil
+	}
+
+	exists, err := d.Exists(installation.BasePath())
+	switch {
+	case err != nil:
+		if isRemote {
+			diagnostics.add("connection", DiagnosticSeverityError, fmt.Sprintf("Failed to access the server files: %s", redactError(installation.Path, err)), "Check that the server is online and that the user has access to the install folder")
+		} else {
+			diagnostics.add("disk", DiagnosticSeverityError, fmt.Sprintf("Failed to access the install: %s", redactError(installation.Path, err)), "Check that SMM has permission to access the install folder")
+		}
+		return diagnostics, nil
+	case !exists:
+		diagnostics.add("disk", DiagnosticSeverityError, "The install folder does not exist", "Remove the install from SMM, or reinstall the game")
+		return diagnostics, nil
+	case isRemote:
+		diagnostics.add("connection", DiagnosticSeverityOK, "Connected to the server", "")
+	}
+
+	f.diagnoseVersionFile(installation, diagnostics)
+	f.diagnoseModsDirectory(d, installation, probeWrite, diagnostics)
+	if !isRemote {
+		diagnoseFreeDiskSpace(installation.Path, diagnostics)
+	}
+	f.diagnoseSML(d, installation, diagnostics)
+	diagnoseSavedPath(d, path, metadata.Info, isRemote, diagnostics)
+
+	l.Info("diagnosed installation", slog.String("severity", string(diagnostics.Severity())))
+
+	return diagnostics, nil
+}
+
+func (f *ficsitCLI) diagnoseVersionFile(installation *cli.Installation, diagnostics *InstallationDiagnostics) {
+	gameVersion, err := installation.GetGameVersion(f.ficsitCli)
+	if err != nil {
+		diagnostics.add("versionFile", DiagnosticSeverityError, fmt.Sprintf("Failed to read the game version file: %s", redactError(installation.Path, err)), "Verify the game files through the launcher, or reinstall the game")
+		return
+	}
+	diagnostics.add("versionFile", DiagnosticSeverityOK, fmt.Sprintf("Game version %d", gameVersion), "")
+}
+
+func (f *ficsitCLI) diagnoseModsDirectory(d disk.Disk, installation *cli.Installation, probeWrite bool, diagnostics *InstallationDiagnostics) {
+	modsDirectory := filepath.Join(installation.BasePath(), "FactoryGame", "Mods")
+	const fix = "Check that the user SMM runs as (or connects with, for servers) has write permission on the game folder"
+
+	if !probeWrite {
+		exists, err := d.Exists(modsDirectory)
+		switch {
+		case err != nil:
+			diagnostics.add("modsDirectory", DiagnosticSeverityWarning, fmt.Sprintf("Failed to check if the Mods directory exists: %s", redactError(installation.Path, err)), "")
+		case !exists:
+			diagnostics.add("modsDirectory", DiagnosticSeverityOK, "The Mods directory does not exist yet, write access was not checked", "")
+		default:
+			diagnostics.add("modsDirectory", DiagnosticSeverityOK, "The Mods directory exists, write access was not checked", "")
+		}
+		return
+	}
+
+	if err := d.MkDir(modsDirectory); err != nil {
+		diagnostics.add("modsDirectory", DiagnosticSeverityError, fmt.Sprintf("Failed to create the Mods directory: %s", redactError(installation.Path, err)), fix)
+		return
+	}
+
+	testFile := filepath.Join(modsDirectory, ".smm-write-test")
+	if err := d.Write(testFile, []byte("SMM")); err != nil {
+		diagnostics.add("modsDirectory", DiagnosticSeverityError, fmt.Sprintf("The Mods directory is not writable: %s", redactError(installation.Path, err)), fix)
+		return
+	}
+	if err := d.Remove(testFile); err != nil {
+		diagnostics.add("modsDirectory", DiagnosticSeverityWarning, fmt.Sprintf("Files in the Mods directory cannot be deleted: %s", redactError(installation.Path, err)), fix)
+		return
+	}
+
+	diagnostics.add("modsDirectory", DiagnosticSeverityOK, "The Mods directory is writable", "")
+}
+
+func diagnoseFreeDiskSpace(path string, diagnostics *InstallationDiagnostics) {
+	usage, err := psUtilDisk.Usage(path)
+	if err != nil {
+		diagnostics.add("diskSpace", DiagnosticSeverityWarning, fmt.Sprintf("Failed to get the free disk space: %s", redactError(path, err)), "")
+		return
+	}
+	message := fmt.Sprintf("%d MiB free", usage.Free/1024/1024)
+	if usage.Free < minFreeDiskSpace {
+		diagnostics.add("diskSpace", DiagnosticSeverityWarning, message, "Free up space on the drive the game is installed on")
+		return
+	}
+	diagnostics.add("diskSpace", DiagnosticSeverityOK, message, "")
+}
+
+func (f *ficsitCLI) diagnoseSML(d disk.Disk, installation *cli.Installation, diagnostics *InstallationDiagnostics) {
+	const fix = "Apply the profile again, by toggling mods off and on"
+
+	lockfile, err := installation.LockFile(f.ficsitCli)
+	if err != nil {
+		diagnostics.add("sml", DiagnosticSeverityError, fmt.Sprintf("Failed to read the lockfile: %s", redactError(installation.Path, err)), fix)
+		return
+	}
+
+	var lockedVersion string
+	if lockfile != nil {
+		if sml, ok := lockfile.Mods["SML"]; ok {
+			lockedVersion = sml.Version
+		}
+	}
+
+	upluginPath := filepath.Join(installation.BasePath(), "FactoryGame", "Mods", "SML", "SML.uplugin")
+	exists, err := d.Exists(upluginPath)
+	if err != nil {
+		diagnostics.add("sml", DiagnosticSeverityWarning, fmt.Sprintf("Failed to check if SML is installed: %s", redactError(installation.Path, err)), "")
+		return
+	}
+
+	if !exists {
+		if lockedVersion != "" {
+			diagnostics.add("sml", DiagnosticSeverityError, fmt.Sprintf("SML %s should be installed, but is missing", lockedVersion), fix)
+			return
+		}
+		diagnostics.add("sml", DiagnosticSeverityOK, "SML is not installed, and no mods need it", "")
+		return
+	}
+
+	data, err := d.Read(upluginPath)
+	if err != nil {
+		diagnostics.add("sml", DiagnosticSeverityError, fmt.Sprintf("Failed to read SML.uplugin: %s", redactError(installation.Path, err)), fix)
+		return
+	}
+	var uplugin cache.UPlugin
+	if err := json.Unmarshal(data, &uplugin); err != nil {
+		diagnostics.add("sml", DiagnosticSeverityError, fmt.Sprintf("SML.uplugin is corrupted: %s", redactError(installation.Path, err)), fix)
+		return
+	}
+
+	switch {
+	case lockedVersion == "":
+		diagnostics.add("sml", DiagnosticSeverityWarning, fmt.Sprintf("SML %s is installed, but not by the current profile", uplugin.SemVersion), "If SML was installed manually, remove it from the Mods directory and install it through SMM")
+	case uplugin.SemVersion != lockedVersion:
+		diagnostics.add("sml", DiagnosticSeverityError, fmt.Sprintf("SML %s is installed, but the lockfile has %s", uplugin.SemVersion, lockedVersion), fix)
+	default:
+		diagnostics.add("sml", DiagnosticSeverityOK, fmt.Sprintf("SML %s is installed", uplugin.SemVersion), "")
+	}
+}
+
+func diagnoseSavedPath(d disk.Disk, installPath string, info *common.Installation, isRemote bool, diagnostics *InstallationDiagnostics) {
+	if info == nil || info.SavedPath == "" {
+		diagnostics.add("savedPath", DiagnosticSeverityWarning, "The Saved directory of the install is unknown", "")
+		return
+	}
+
+	var exists bool
+	var err error
+	if isRemote {
+		exists, err = d.Exists(info.SavedPath)
+	} else {
+		// The Saved directory of clients is not in the install folder
+		_, err = os.Stat(info.SavedPath)
+		exists = err == nil
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	}
+
+	switch {
+	case err != nil:
+		diagnostics.add("savedPath", DiagnosticSeverityWarning, fmt.Sprintf("Failed to access the Saved directory: %s", redactError(installPath, err)), "Check that SMM has permission to access the Saved directory")
+	case !exists:
+		diagnostics.add("savedPath", DiagnosticSeverityWarning, "The Saved directory does not exist, so no logs are available", "Launch the game once")
+	default:
+		diagnostics.add("savedPath", DiagnosticSeverityOK, "The Saved directory is reachable", "")
+	}
+}
+
+// redactError returns the message of the error, without the address and credentials of remote installs,
+// since the diagnostics are included in the debug info
+func redactError(installPath string, err error) string {
+	message := strings.ReplaceAll(err.Error(), installPath, utils.RedactPath(installPath))
+	parsed, parseErr := url.Parse(installPath)
+	if parseErr != nil || parsed.Host == "" {
+		return message
+	}
+	if parsed.User != nil {
+		if password, ok := parsed.User.Password(); ok && password != "" {
+			message = strings.ReplaceAll(message, password, "pass")
+		}
+	}
+	return strings.ReplaceAll(message, parsed.Hostname(), "******")
+}
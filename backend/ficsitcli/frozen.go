@@ -0,0 +1,351 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	"github.com/satisfactorymodding/ficsit-cli/cli/cache"
+	"github.com/satisfactorymodding/ficsit-cli/cli/disk"
+	ficsitUtils "github.com/satisfactorymodding/ficsit-cli/utils"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
+)
+
+// Frozen installs use the exact versions of a lockfile, similar to `npm ci`.
+// Unlike the regular apply, the resolver is never used, so the result is the same on every install,
+// and anything that would need resolving is an error instead
+
+// FrozenLockfileError lists every locked mod that cannot be installed as is
+type FrozenLockfileError struct {
+	Install  string   `json:"install"`
+	Problems []string `json:"problems"`
+}
+
+func (e FrozenLockfileError) Error() string {
+	return fmt.Sprintf("lockfile cannot be installed on %s without resolving: %s", e.Install, strings.Join(e.Problems, "; "))
+}
+
+var serverTargets = map[string]bool{
+	string(resolver.TargetNameWindowsServer): true,
+	string(resolver.TargetNameLinuxServer):   true,
+}
+
+// ApplyFrozen installs the lockfile of the selected install, exactly as is, on all installs using the selected profile
+func (f *ficsitCLI) ApplyFrozen() error {
+	profileName := f.GetSelectedProfile()
+	if profileName == nil {
+		return fmt.Errorf("no profile selected")
+	}
+	selectedInstallation := f.GetSelectedInstall()
+	return f.action(ActionApplyFrozen, newSimpleItem(*profileName), installProfileScope(selectedInstallation), func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		lockfile, err := selectedInstallation.LockFile(f.ficsitCli)
+		if err != nil {
+			l.Error("failed to read lockfile", slog.Any("error", err))
+			return fmt.Errorf("failed to read lockfile: %w", err)
+		}
+		if lockfile == nil {
+			return fmt.Errorf("the selected install has no lockfile for this profile")
+		}
+		return f.applyFrozen(l, selectedInstallation, lockfile, taskChannel)
+	})
+}
+
+func (f *ficsitCLI) applyFrozen(l *slog.Logger, selectedInstall *cli.Installation, lockfile *resolver.LockFile, taskChannel chan<- taskUpdate) error {
+	defer close(taskChannel)
+
+	installsToApply, profile, err := f.getInstallsToApply(selectedInstall)
+	if err != nil {
+		return err
+	}
+
+	f.EmitModsChange()
+	defer f.EmitModsChange()
+
+	// Validate everything before changing any install
+	frozenLockfiles := make([]*resolver.LockFile, len(installsToApply))
+	for i, installTarget := range installsToApply {
+		if installTarget.install.Vanilla {
+			// Same as the regular apply, vanilla installs get no mods
+			frozenLockfiles[i] = resolver.NewLockfile()
+			continue
+		}
+		frozenLockfile, err := f.validateFrozenLockfile(lockfile, installTarget)
+		if err != nil {
+			l.Error("lockfile cannot be installed as is", slog.String("install", installTarget.install.Path), slog.Any("error", err))
+			return err
+		}
+		frozenLockfiles[i] = frozenLockfile
+	}
+
+	// The lockfiles of the installs and the required targets of the profile are kept in sync,
+	// so a later regular apply finds every target it requires locked, and does not resolve again
+	syncedLockfile, requiredTargets := mergeFrozenLockfiles(installsToApply, frozenLockfiles)
+	_ = f.updateState(func() error {
+		profile.RequiredTargets = requiredTargets
+		err := f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
+
+	var errg errgroup.Group
+	for i, installTarget := range installsToApply {
+		frozenLockfile := frozenLockfiles[i]
+		errg.Go(func() error {
+			return f.recordApply(installTarget.install, func() error {
+				err := f.installFrozen(installTarget, frozenLockfile, taskChannel)
+				if err != nil {
+					return fmt.Errorf("failed to install %s: %w", installTarget.install.Path, err)
+				}
+				if installTarget.install.Vanilla {
+					// Same as the regular apply, the lockfile of vanilla installs is left as is
+					return nil
+				}
+				err = installTarget.install.WriteLockFile(f.ficsitCli, syncedLockfile)
+				if err != nil {
+					return fmt.Errorf("failed to write lockfile: %w", err)
+				}
+				return nil
+			})
+		})
+	}
+
+	return errg.Wait() //nolint:wrapcheck
+}
+
+// mergeFrozenLockfiles combines the validated lockfiles of the modded installs into one with the targets of all of them,
+// and returns those targets
+func mergeFrozenLockfiles(installsToApply []installWithTarget, frozenLockfiles []*resolver.LockFile) (*resolver.LockFile, []resolver.TargetName) {
+	merged := resolver.NewLockfile()
+	var targets []resolver.TargetName
+	for i, installTarget := range installsToApply {
+		if installTarget.install.Vanilla {
+			continue
+		}
+		if !slices.Contains(targets, resolver.TargetName(installTarget.targetName)) {
+			targets = append(targets, resolver.TargetName(installTarget.targetName))
+		}
+		for modReference, lockedMod := range frozenLockfiles[i].Mods {
+			mergedMod, ok := merged.Mods[modReference]
+			if !ok {
+				mergedMod = resolver.LockedMod{
+					Version:      lockedMod.Version,
+					Dependencies: lockedMod.Dependencies,
+					Targets:      make(map[string]resolver.LockedModTarget),
+				}
+			}
+			maps.Copy(mergedMod.Targets, lockedMod.Targets)
+			merged.Mods[modReference] = mergedMod
+		}
+	}
+	slices.Sort(targets)
+	return merged, targets
+}
+
+// validateFrozenLockfile checks that every locked version exists, is compatible with the install's game version,
+// and has a build for its target. Returns the lockfile with the download information of the install's target filled in
+func (f *ficsitCLI) validateFrozenLockfile(lockfile *resolver.LockFile, installTarget installWithTarget) (*resolver.LockFile, error) {
+	gameVersion, err := installTarget.install.GetGameVersion(f.ficsitCli)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game version: %w", err)
+	}
+	gameVersionSemver, err := semver.NewVersion(fmt.Sprintf("%d", gameVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse game version: %w", err)
+	}
+
+	result := resolver.NewLockfile()
+	var problems []string
+	for modReference, lockedMod := range lockfile.Mods {
+		modVersion, err := f.getModVersion(modReference, lockedMod.Version)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s@%s: %s", modReference, lockedMod.Version, err.Error()))
+			continue
+		}
+		if modVersion == nil {
+			problems = append(problems, fmt.Sprintf("%s@%s is not available", modReference, lockedMod.Version))
+			continue
+		}
+
+		if modVersion.GameVersion != "" {
+			gameVersionConstraint, err := semver.NewConstraint(modVersion.GameVersion)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s@%s has an invalid game version constraint %s", modReference, lockedMod.Version, modVersion.GameVersion))
+				continue
+			}
+			if !gameVersionConstraint.Contains(gameVersionSemver) {
+				problems = append(problems, fmt.Sprintf("%s@%s requires game version %s, but %d is installed", modReference, lockedMod.Version, modVersion.GameVersion, gameVersion))
+				continue
+			}
+		}
+
+		var providerTarget *resolver.Target
+		for i := range modVersion.Targets {
+			if string(modVersion.Targets[i].TargetName) == installTarget.targetName {
+				providerTarget = &modVersion.Targets[i]
+				break
+			}
+		}
+
+		lockedTarget, hasLockedTarget := lockedMod.Targets[installTarget.targetName]
+		switch {
+		case providerTarget == nil && hasLockedTarget:
+			problems = append(problems, fmt.Sprintf("%s@%s is no longer available for %s", modReference, lockedMod.Version, installTarget.targetName))
+			continue
+		case providerTarget == nil:
+			// Same as ficsit-cli, mods that are not required on servers do not need a server build
+			if serverTargets[installTarget.targetName] && !modVersion.RequiredOnRemote {
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("%s@%s has no build for %s", modReference, lockedMod.Version, installTarget.targetName))
+			continue
+		case hasLockedTarget && lockedTarget.Hash != "" && lockedTarget.Hash != providerTarget.Hash:
+			problems = append(problems, fmt.Sprintf("%s@%s for %s does not match the locked hash", modReference, lockedMod.Version, installTarget.targetName))
+			continue
+		}
+
+		result.Mods[modReference] = resolver.LockedMod{
+			Version:      lockedMod.Version,
+			Dependencies: lockedMod.Dependencies,
+			Targets: map[string]resolver.LockedModTarget{
+				installTarget.targetName: {
+					Link: providerTarget.Link,
+					Hash: providerTarget.Hash,
+				},
+			},
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, FrozenLockfileError{
+			Install:  installTarget.install.Path,
+			Problems: problems,
+		}
+	}
+
+	return result, nil
+}
+
+// installFrozen makes the Mods directory of the install match the lockfile
+func (f *ficsitCLI) installFrozen(installTarget installWithTarget, lockfile *resolver.LockFile, taskChannel chan<- taskUpdate) error {
+	installation := installTarget.install
+
+	d, err := installation.GetDisk()
+	if err != nil {
+		return fmt.Errorf("failed to get disk: %w", err)
+	}
+
+	modsDirectory := filepath.Join(installation.BasePath(), "FactoryGame", "Mods")
+	if err := d.MkDir(modsDirectory); err != nil {
+		return fmt.Errorf("failed creating Mods directory: %w", err)
+	}
+
+	err = removeUnlockedMods(d, modsDirectory, lockfile)
+	if err != nil {
+		return err
+	}
+
+	downloadSemaphore := make(chan int, viper.GetInt("concurrent-downloads"))
+	defer close(downloadSemaphore)
+
+	var errg errgroup.Group
+	for modReference, lockedMod := range lockfile.Mods {
+		target, ok := lockedMod.Targets[installTarget.targetName]
+		if !ok {
+			continue
+		}
+		errg.Go(func() error {
+			err := downloadAndExtractFrozenMod(d, modsDirectory, modReference, lockedMod.Version, target, installTarget.targetName, downloadSemaphore, taskChannel)
+			if err != nil {
+				return fmt.Errorf("failed to install %s@%s: %w", modReference, lockedMod.Version, err)
+			}
+			return nil
+		})
+	}
+
+	return errg.Wait() //nolint:wrapcheck
+}
+
+// removeUnlockedMods deletes the mods installed by SMM that are not in the lockfile
+func removeUnlockedMods(d disk.Disk, modsDirectory string, lockfile *resolver.LockFile) error {
+	entries, err := d.ReadDir(modsDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to read mods directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, ok := lockfile.Mods[entry.Name()]; ok {
+			continue
+		}
+		modDir := filepath.Join(modsDirectory, entry.Name())
+		exists, err := d.Exists(filepath.Join(modDir, ".smm"))
+		if err != nil {
+			return fmt.Errorf("failed to check mod directory: %w", err)
+		}
+		if !exists {
+			// Not installed by SMM
+			continue
+		}
+		slog.Info("deleting mod", slog.String("mod_reference", entry.Name()))
+		if err := d.Remove(modDir); err != nil {
+			return fmt.Errorf("failed to delete mod directory: %w", err)
+		}
+	}
+	return nil
+}
+
+func downloadAndExtractFrozenMod(d disk.Disk, modsDirectory string, modReference string, version string, target resolver.LockedModTarget, targetName string, downloadSemaphore chan int, taskChannel chan<- taskUpdate) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	forwardProgress := func(task string) chan ficsitUtils.GenericProgress {
+		updates := make(chan ficsitUtils.GenericProgress)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for update := range updates {
+				taskChannel <- taskUpdate{
+					taskName: fmt.Sprintf("%s:%s:%s:%s", modReference, version, targetName, task),
+					progress: utils.Progress{
+						Current: update.Completed,
+						Total:   update.Total,
+					},
+				}
+			}
+		}()
+		return updates
+	}
+
+	downloadUpdates := forwardProgress("download")
+	reader, size, err := cache.DownloadOrCache(modReference+"_"+version+"_"+targetName+".zip", target.Hash, target.Link, downloadUpdates, downloadSemaphore)
+	close(downloadUpdates)
+	if err != nil {
+		return fmt.Errorf("failed to download %s from: %s: %w", modReference, target.Link, err)
+	}
+	defer reader.Close()
+
+	extractUpdates := forwardProgress("extract")
+	err = ficsitUtils.ExtractMod(reader, size, filepath.Join(modsDirectory, modReference), target.Hash, extractUpdates, d)
+	close(extractUpdates)
+	if err != nil {
+		return fmt.Errorf("could not extract %s: %w", modReference, err)
+	}
+
+	return nil
+}
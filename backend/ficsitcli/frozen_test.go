@@ -0,0 +1,46 @@
+package ficsitcli
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+)
+
+func TestMergeFrozenLockfiles(t *testing.T) {
+	installsToApply := []installWithTarget{
+		{install: &cli.Installation{Path: "client"}, targetName: string(resolver.TargetNameWindows)},
+		{install: &cli.Installation{Path: "server"}, targetName: string(resolver.TargetNameLinuxServer)},
+		{install: &cli.Installation{Path: "vanilla", Vanilla: true}, targetName: string(resolver.TargetNameWindows)},
+	}
+	frozenLockfiles := []*resolver.LockFile{
+		{Mods: map[string]resolver.LockedMod{
+			"SML":      {Version: "3.7.0", Targets: map[string]resolver.LockedModTarget{"Windows": {Hash: "sml-windows"}}},
+			"ClientUI": {Version: "1.0.0", Targets: map[string]resolver.LockedModTarget{"Windows": {Hash: "ui-windows"}}},
+		}},
+		{Mods: map[string]resolver.LockedMod{
+			"SML": {Version: "3.7.0", Targets: map[string]resolver.LockedModTarget{"LinuxServer": {Hash: "sml-linux"}}},
+		}},
+		resolver.NewLockfile(),
+	}
+
+	merged, targets := mergeFrozenLockfiles(installsToApply, frozenLockfiles)
+
+	wantTargets := []resolver.TargetName{resolver.TargetNameLinuxServer, resolver.TargetNameWindows}
+	if !slices.Equal(targets, wantTargets) {
+		t.Errorf("targets = %v, want %v", targets, wantTargets)
+	}
+
+	wantSMMTargets := map[string]resolver.LockedModTarget{"Windows": {Hash: "sml-windows"}, "LinuxServer": {Hash: "sml-linux"}}
+	if got := merged.Mods["SML"]; got.Version != "3.7.0" || !maps.Equal(got.Targets, wantSMMTargets) {
+		t.Errorf("SML = %v, want 3.7.0 with targets %v", got, wantSMMTargets)
+	}
+	if got := merged.Mods["ClientUI"]; len(got.Targets) != 1 {
+		t.Errorf("ClientUI = %v, want only the client target", got)
+	}
+	if got := frozenLockfiles[0].Mods["SML"].Targets; len(got) != 1 {
+		t.Errorf("mergeFrozenLockfiles() modified the validated lockfile: %v", got)
+	}
+}
@@ -0,0 +1,301 @@
+package ficsitcli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+)
+
+// Game updates change many files at once, so changes are only handled once they settle
+const installChangeDebounce = 2 * time.Second
+
+// installWatcher watches the directories of the version files and the Mods directory of the local installs
+type installWatcher struct {
+	watcher *fsnotify.Watcher
+	// Watched directory -> install path
+	watchedDirs map[string]string
+	timers      map[string]*time.Timer
+	mutex       sync.Mutex
+}
+
+// StartInstallationsWatcher watches the version file and the Mods directory of the local installs, and re-detects
+// the game version and the incompatible mods when they change, so a game update or mods changed outside SMM are noticed.
+// Changes while an action is running on the install are ignored, since SMM changes the Mods directory on every apply
+func (f *ficsitCLI) StartInstallationsWatcher() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Error("failed to create installations watcher", slog.Any("error", err))
+		return
+	}
+
+	f.installWatcher = &installWatcher{
+		watcher:     watcher,
+		watchedDirs: make(map[string]string),
+		timers:      make(map[string]*time.Timer),
+	}
+
+	f.installationMetadata.Range(func(installPath string, metadata installationMetadata) bool {
+		if metadata.Info != nil && metadata.Info.Location == common.LocationTypeLocal {
+			f.watchInstallation(installPath)
+		}
+		return true
+	})
+
+	go f.installWatcher.run(f.redetectInstallation, f.actionRunningOn)
+}
+
+// StopInstallationsWatcher stops watching the installs
+func (f *ficsitCLI) StopInstallationsWatcher() {
+	if f.installWatcher == nil {
+		return
+	}
+	f.installWatcher.mutex.Lock()
+	for _, timer := range f.installWatcher.timers {
+		timer.Stop()
+	}
+	f.installWatcher.mutex.Unlock()
+	if err := f.installWatcher.watcher.Close(); err != nil {
+		slog.Warn("failed to close installations watcher", slog.Any("error", err))
+	}
+}
+
+// watchInstallation starts watching the version file and the Mods directory of a local install,
+// so installs added later are watched too
+func (f *ficsitCLI) watchInstallation(installPath string) {
+	w := f.installWatcher
+	if w == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.watch(installPath)
+}
+
+// rewatchInstallation watches the directories of an already watched install that did not exist yet,
+// since the first apply creates the Mods directory
+func (f *ficsitCLI) rewatchInstallation(installPath string) {
+	w := f.installWatcher
+	if w == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, watchedInstall := range w.watchedDirs {
+		if watchedInstall == installPath {
+			w.watch(installPath)
+			return
+		}
+	}
+}
+
+func (w *installWatcher) watch(installPath string) {
+	var dirs []string
+	for _, versionFilePath := range common.VersionFilePaths(installPath) {
+		dirs = append(dirs, filepath.Dir(versionFilePath))
+	}
+	dirs = append(dirs, modsDirectory(installPath))
+
+	for _, dir := range dirs {
+		if _, ok := w.watchedDirs[dir]; ok {
+			continue
+		}
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := w.watcher.Add(dir); err != nil {
+			slog.Warn("failed to watch directory", slog.String("path", dir), slog.Any("error", err))
+			continue
+		}
+		w.watchedDirs[dir] = installPath
+	}
+}
+
+// unwatchInstallation stops watching a removed install
+func (f *ficsitCLI) unwatchInstallation(installPath string) {
+	w := f.installWatcher
+	if w == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for dir, watchedInstall := range w.watchedDirs {
+		if watchedInstall != installPath {
+			continue
+		}
+		_ = w.watcher.Remove(dir)
+		delete(w.watchedDirs, dir)
+	}
+	if timer, ok := w.timers[installPath]; ok {
+		timer.Stop()
+		delete(w.timers, installPath)
+	}
+}
+
+func (w *installWatcher) run(onChange func(installPath string), isBusy func(installPath string) bool) {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			dir := filepath.Dir(event.Name)
+			w.mutex.Lock()
+			installPath, ok := w.watchedDirs[dir]
+			w.mutex.Unlock()
+			if !ok {
+				continue
+			}
+			if dir != modsDirectory(installPath) && !strings.HasSuffix(event.Name, ".version") {
+				// Only the version file is relevant in the binaries directory
+				continue
+			}
+			if isBusy(installPath) {
+				continue
+			}
+			w.mutex.Lock()
+			if timer, ok := w.timers[installPath]; ok {
+				timer.Reset(installChangeDebounce)
+			} else {
+				w.timers[installPath] = time.AfterFunc(installChangeDebounce, func() {
+					w.mutex.Lock()
+					delete(w.timers, installPath)
+					w.mutex.Unlock()
+					onChange(installPath)
+				})
+			}
+			w.mutex.Unlock()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("installations watcher error", slog.Any("error", err))
+		}
+	}
+}
+
+// modsDirectory is the directory SMM installs the mods of a local install in
+func modsDirectory(installPath string) string {
+	return filepath.Join(installPath, "FactoryGame", "Mods")
+}
+
+// actionRunningOn returns whether an action is running on the install
+func (f *ficsitCLI) actionRunningOn(installPath string) bool {
+	f.progressMutex.Lock()
+	defer f.progressMutex.Unlock()
+	for _, progress := range f.runningActions {
+		if slices.Contains(progress.Installs, installPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// redetectInstallation updates the game info of a local install, and flags the installed mods
+// that are not compatible with its game version
+func (f *ficsitCLI) redetectInstallation(installPath string) {
+	l := slog.With(slog.String("task", "redetectInstallation"), slog.String("install", installPath))
+
+	installation := f.GetInstallation(installPath)
+	if installation == nil {
+		return
+	}
+
+	// The Saved directory does not change when the game is updated, so the platform it depends on is not needed
+	installType, version, _, err := common.GetGameInfo(installPath, common.NativePlatform())
+	if err != nil {
+		l.Warn("failed to get game info", slog.Any("error", err))
+		return
+	}
+
+	incompatibleMods := f.findIncompatibleMods(l, installation, version)
+
+	// The metadata may have changed while the game info was read, so only the detected fields are replaced
+	updated := false
+	f.installationMetadata.Compute(installPath, func(metadata installationMetadata, loaded bool) (installationMetadata, bool) {
+		if !loaded || metadata.Info == nil {
+			// Do not add metadata for an install removed in the meantime
+			return metadata, !loaded
+		}
+		updated = true
+		info := *metadata.Info
+		if info.Version != version {
+			l.Info("game version changed", slog.Int("from", info.Version), slog.Int("to", version))
+		}
+		info.Version = version
+		info.Type = installType
+		return installationMetadata{
+			State:            metadata.State,
+			Info:             &info,
+			IncompatibleMods: incompatibleMods,
+		}, false
+	})
+	if !updated {
+		return
+	}
+
+	f.EmitGlobals()
+}
+
+// findIncompatibleMods returns the mods in the lockfile of the install whose locked version does not support the game version
+func (f *ficsitCLI) findIncompatibleMods(l *slog.Logger, installation *cli.Installation, gameVersion int) []string {
+	incompatible := []string{}
+
+	lockfile, err := installation.LockFile(f.ficsitCli)
+	if err != nil {
+		l.Warn("failed to read lockfile", slog.Any("error", err))
+		return incompatible
+	}
+	if lockfile == nil {
+		return incompatible
+	}
+
+	gameVersionSemver, err := semver.NewVersion(fmt.Sprintf("%d", gameVersion))
+	if err != nil {
+		l.Warn("failed to parse game version", slog.Int("gameVersion", gameVersion), slog.Any("error", err))
+		return incompatible
+	}
+
+	for modReference, lockedMod := range lockfile.Mods {
+		modVersions, err := f.ficsitCli.Provider.ModVersionsWithDependencies(context.TODO(), modReference)
+		if err != nil {
+			l.Warn("failed to get mod versions", slog.String("mod", modReference), slog.Any("error", err))
+			continue
+		}
+		for _, modVersion := range modVersions {
+			if modVersion.Version != lockedMod.Version || modVersion.GameVersion == "" {
+				continue
+			}
+			gameVersionConstraint, err := semver.NewConstraint(modVersion.GameVersion)
+			if err != nil {
+				l.Warn("failed to parse game version constraint", slog.String("mod", modReference), slog.Any("error", err))
+				break
+			}
+			if !gameVersionConstraint.Contains(gameVersionSemver) {
+				incompatible = append(incompatible, modReference)
+			}
+			break
+		}
+	}
+
+	sort.Strings(incompatible)
+	return incompatible
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 
@@ -39,11 +40,14 @@ func (f *ficsitCLI) ensureSelectedInstallationIsValid() {
 	if !f.isValidInstall(f.ficsitCli.Installations.SelectedInstallation) {
 		filteredInstalls := f.GetInstallations()
 		if len(filteredInstalls) > 0 {
-			f.ficsitCli.Installations.SelectedInstallation = filteredInstalls[0]
-			err := f.ficsitCli.Installations.Save()
-			if err != nil {
-				slog.Error("failed to save selected installation", slog.Any("error", err))
-			}
+			_ = f.updateState(func() error {
+				f.ficsitCli.Installations.SelectedInstallation = filteredInstalls[0]
+				err := f.ficsitCli.Installations.Save()
+				if err != nil {
+					slog.Error("failed to save selected installation", slog.Any("error", err))
+				}
+				return nil
+			})
 			f.EmitGlobals()
 		}
 	}
@@ -89,29 +93,41 @@ func (f *ficsitCLI) GetInstallation(path string) *cli.Installation {
 	return f.ficsitCli.Installations.GetInstallation(path)
 }
 
+// SelectInstall changes no mods, so unlike the other operations it is not an action,
+// and an install can be selected while actions are running on other installs
 func (f *ficsitCLI) SelectInstall(path string) error {
-	return f.action(ActionSelectInstall, newSimpleItem(path), func(l *slog.Logger, _ chan<- taskUpdate) error {
-		if !f.isValidInstall(path) {
-			return fmt.Errorf("invalid installation: %s", path)
-		}
+	l := slog.With(slog.String("task", "selectInstall"), slog.String("install", path))
+
+	if !f.isValidInstall(path) {
+		return fmt.Errorf("invalid installation: %s", path)
+	}
+
+	changed := false
+	err := f.updateState(func() error {
 		if f.ficsitCli.Installations.SelectedInstallation == path {
 			return nil
 		}
-		installation := f.ficsitCli.Installations.GetInstallation(path)
-		if installation == nil {
+		if f.ficsitCli.Installations.GetInstallation(path) == nil {
 			return fmt.Errorf("installation %s not found", path)
 		}
 
 		f.ficsitCli.Installations.SelectedInstallation = path
+		changed = true
 		err := f.ficsitCli.Installations.Save()
 		if err != nil {
 			l.Error("failed to save selected installation", slog.Any("error", err))
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
+	if changed {
 		f.EmitGlobals()
 		f.EmitModsChange()
-		return nil
-	})
+	}
+	return nil
 }
 
 func (f *ficsitCLI) GetSelectedInstall() *cli.Installation {
@@ -125,24 +141,26 @@ func (f *ficsitCLI) SetModsEnabled(enabled bool) error {
 	} else {
 		item = newSimpleItem("false")
 	}
-	return f.action(ActionToggleMods, item, func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
-
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
+	return f.action(ActionToggleMods, item, installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 		l = l.With(slog.String("install", selectedInstallation.Path))
 
-		selectedInstallation.Vanilla = !enabled
-		err := f.ficsitCli.Installations.Save()
-		if err != nil {
-			l.Error("failed to save vanilla state of install", slog.Any("error", err))
-		}
+		_ = f.updateState(func() error {
+			selectedInstallation.Vanilla = !enabled
+			err := f.ficsitCli.Installations.Save()
+			if err != nil {
+				l.Error("failed to save vanilla state of install", slog.Any("error", err))
+			}
+			return nil
+		})
 
 		f.EmitGlobals()
 
-		installErr := f.apply(l, taskUpdates)
+		installErr := f.apply(l, selectedInstallation, taskUpdates)
 
 		if installErr != nil {
 			l.Error("failed to validate install", slog.Any("error", installErr))
@@ -167,7 +185,10 @@ func (f *ficsitCLI) GetSelectedInstallProfileMods() map[string]cli.ProfileMod {
 	if profile == nil {
 		return make(map[string]cli.ProfileMod)
 	}
-	return profile.Mods
+	// Copied while holding the state lock, since actions can be changing it
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+	return maps.Clone(profile.Mods)
 }
 
 func (f *ficsitCLI) GetSelectedInstallLockfileMods() (map[string]resolver.LockedMod, error) {
@@ -0,0 +1,118 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/localservers"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+// AddLocalServer adds a dedicated server directory on this machine that no launcher knows about.
+// It is validated again on every startup, like the installs found through launchers
+func (f *ficsitCLI) AddLocalServer(path string) error {
+	l := slog.With(slog.String("task", "addLocalServer"), slog.String("path", path))
+
+	install, err := localservers.GetInstallation(path)
+	if err != nil {
+		return fmt.Errorf("invalid server directory: %w", err)
+	}
+
+	// The new install uses the fallback profile, so it must not change while the profile is being applied
+	fallbackProfile := f.GetFallbackProfile()
+	_, unlock, err := f.lockScope(installScope(install.Path).with(profileScope(fallbackProfile)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = f.updateState(func() error {
+		for _, existing := range f.ficsitCli.Installations.Installations {
+			if common.OsPathEqual(existing.Path, install.Path) {
+				return fmt.Errorf("installation already exists")
+			}
+		}
+
+		_, err := f.ficsitCli.Installations.AddInstallation(f.ficsitCli, install.Path, fallbackProfile)
+		if err != nil {
+			return fmt.Errorf("failed to add installation: %w", err)
+		}
+
+		err = f.ficsitCli.Installations.Save()
+		if err != nil {
+			l.Error("failed to save installations", slog.Any("error", err))
+		}
+
+		settings.Settings.LocalServers = append(settings.Settings.LocalServers, install.Path)
+		_ = settings.SaveSettings()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.installationMetadata.Store(install.Path, installationMetadata{
+		State: InstallStateValid,
+		Info:  install,
+	})
+	f.watchInstallation(install.Path)
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+func (f *ficsitCLI) RemoveLocalServer(path string) error {
+	path = filepath.Clean(path)
+
+	_, unlock, err := f.lockScope(installScope(path))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = f.updateState(func() error {
+		if !slices.Contains(settings.Settings.LocalServers, path) {
+			return fmt.Errorf("installation is not a local server")
+		}
+
+		if f.ficsitCli.Installations.GetInstallation(path) != nil {
+			err := f.ficsitCli.Installations.DeleteInstallation(path)
+			if err != nil {
+				return fmt.Errorf("failed to delete installation: %w", err)
+			}
+			err = f.ficsitCli.Installations.Save()
+			if err != nil {
+				slog.Error("failed to save installations", slog.Any("error", err))
+			}
+		}
+
+		settings.Settings.LocalServers = slices.DeleteFunc(settings.Settings.LocalServers, func(server string) bool {
+			return server == path
+		})
+		_ = settings.SaveSettings()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.installationMetadata.Delete(path)
+	f.unwatchInstallation(path)
+
+	f.ensureSelectedInstallationIsValid()
+	f.EmitGlobals()
+
+	return nil
+}
+
+// GetLocalServers returns the dedicated server directories added with AddLocalServer
+func (f *ficsitCLI) GetLocalServers() []string {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	return slices.Clone(settings.Settings.LocalServers)
+}
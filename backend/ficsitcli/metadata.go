@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,6 +26,14 @@ func (f *ficsitCLI) initLocalInstallationsMetadata() error {
 
 	for _, findErr := range findErrors {
 		slog.Info("failed to find installations", slog.Any("error", findErr))
+
+		// Local servers that no longer validate must not be treated as remote servers
+		var installFindErr common.InstallFindError
+		if errors.As(findErr, &installFindErr) && slices.Contains(settings.Settings.LocalServers, installFindErr.Path) {
+			f.installationMetadata.Store(installFindErr.Path, installationMetadata{
+				State: InstallStateInvalid,
+			})
+		}
 	}
 
 	fallbackProfile := f.GetFallbackProfile()
@@ -172,7 +181,15 @@ func (f *ficsitCLI) getRemoteServerMetadata(installation *cli.Installation) (*co
 		return nil, ErrInstallNotServer
 	}
 
-	branch := common.BranchStable // TODO: Do we have a way to detect this for remote installs?
+	branch := common.BranchStable
+	d, err := installation.GetDisk()
+	if err == nil {
+		branch, err = common.DetectBranch(d, installation.BasePath())
+	}
+	if err != nil {
+		slog.Warn("failed to detect branch of remote server, assuming stable", slog.String("path", installation.Path), slog.Any("error", err))
+		branch = common.BranchStable
+	}
 
 	remoteName := settings.Settings.RemoteNames[remoteKey(installation.Path)]
 
@@ -6,13 +6,12 @@ import (
 )
 
 func (f *ficsitCLI) InstallMod(mod string) error {
-	return f.action(ActionInstall, newSimpleItem(mod), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
-
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
+	return f.action(ActionInstall, newSimpleItem(mod), installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 		l = l.With(
 			slog.String("install", selectedInstallation.Path),
 			slog.String("profile", selectedInstallation.Profile),
@@ -21,18 +20,26 @@ func (f *ficsitCLI) InstallMod(mod string) error {
 		profileName := selectedInstallation.Profile
 		profile := f.GetProfile(profileName)
 
-		profileErr := profile.AddMod(mod, ">=0.0.0")
-		if profileErr != nil {
-			l.Error("failed to add mod", slog.Any("error", profileErr))
-			return fmt.Errorf("failed to add mod: %s@latest: %w", mod, profileErr)
-		}
-
-		err := f.ficsitCli.Profiles.Save()
+		err := f.updateState(func() error {
+			profileErr := profile.AddMod(mod, ">=0.0.0")
+			if profileErr != nil {
+				l.Error("failed to add mod", slog.Any("error", profileErr))
+				return fmt.Errorf("failed to add mod: %s@latest: %w", mod, profileErr)
+			}
+
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
 		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
+			return err
 		}
 
-		installErr := f.apply(l, taskUpdates)
+		f.markProfileModified(selectedInstallation.Profile)
+
+		installErr := f.apply(l, selectedInstallation, taskUpdates)
 
 		if installErr != nil {
 			l.Error("failed to install", slog.Any("error", installErr))
@@ -44,13 +51,12 @@ func (f *ficsitCLI) InstallMod(mod string) error {
 }
 
 func (f *ficsitCLI) InstallModVersion(mod string, version string) error {
-	return f.action(ActionInstall, newItem(mod, version), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
-
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
+	return f.action(ActionInstall, newItem(mod, version), installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 		l = l.With(
 			slog.String("install", selectedInstallation.Path),
 			slog.String("profile", selectedInstallation.Profile),
@@ -58,18 +64,26 @@ func (f *ficsitCLI) InstallModVersion(mod string, version string) error {
 
 		profile := f.GetProfile(selectedInstallation.Profile)
 
-		profileErr := profile.AddMod(mod, version)
-		if profileErr != nil {
-			l.Error("failed to add mod", slog.Any("error", profileErr))
-			return fmt.Errorf("failed to add mod: %s@%s: %w", mod, version, profileErr)
-		}
-
-		err := f.ficsitCli.Profiles.Save()
+		err := f.updateState(func() error {
+			profileErr := profile.AddMod(mod, version)
+			if profileErr != nil {
+				l.Error("failed to add mod", slog.Any("error", profileErr))
+				return fmt.Errorf("failed to add mod: %s@%s: %w", mod, version, profileErr)
+			}
+
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
 		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
+			return err
 		}
 
-		installErr := f.apply(l, taskUpdates)
+		f.markProfileModified(selectedInstallation.Profile)
+
+		installErr := f.apply(l, selectedInstallation, taskUpdates)
 
 		if installErr != nil {
 			l.Error("failed to install", slog.Any("error", installErr))
@@ -81,13 +95,12 @@ func (f *ficsitCLI) InstallModVersion(mod string, version string) error {
 }
 
 func (f *ficsitCLI) RemoveMod(mod string) error {
-	return f.action(ActionUninstall, newSimpleItem(mod), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
-
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
+	return f.action(ActionUninstall, newSimpleItem(mod), installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 		l = l.With(
 			slog.String("install", selectedInstallation.Path),
 			slog.String("profile", selectedInstallation.Profile),
@@ -95,14 +108,19 @@ func (f *ficsitCLI) RemoveMod(mod string) error {
 
 		profile := f.GetProfile(selectedInstallation.Profile)
 
-		profile.RemoveMod(mod)
+		_ = f.updateState(func() error {
+			profile.RemoveMod(mod)
 
-		err := f.ficsitCli.Profiles.Save()
-		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
-		}
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
+
+		f.markProfileModified(selectedInstallation.Profile)
 
-		installErr := f.apply(l, taskUpdates)
+		installErr := f.apply(l, selectedInstallation, taskUpdates)
 
 		if installErr != nil {
 			l.Error("failed to install", slog.Any("error", installErr))
@@ -114,13 +132,12 @@ func (f *ficsitCLI) RemoveMod(mod string) error {
 }
 
 func (f *ficsitCLI) EnableMod(mod string) error {
-	return f.action(ActionEnable, newSimpleItem(mod), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
-
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
+	return f.action(ActionEnable, newSimpleItem(mod), installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 		l = l.With(
 			slog.String("install", selectedInstallation.Path),
 			slog.String("profile", selectedInstallation.Profile),
@@ -128,14 +145,19 @@ func (f *ficsitCLI) EnableMod(mod string) error {
 
 		profile := f.GetProfile(selectedInstallation.Profile)
 
-		profile.SetModEnabled(mod, true)
+		_ = f.updateState(func() error {
+			profile.SetModEnabled(mod, true)
 
-		err := f.ficsitCli.Profiles.Save()
-		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
-		}
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
 
-		installErr := f.apply(l, taskUpdates)
+		f.markProfileModified(selectedInstallation.Profile)
+
+		installErr := f.apply(l, selectedInstallation, taskUpdates)
 
 		if installErr != nil {
 			l.Error("failed to install", slog.Any("error", installErr))
@@ -147,13 +169,12 @@ func (f *ficsitCLI) EnableMod(mod string) error {
 }
 
 func (f *ficsitCLI) DisableMod(mod string) error {
-	return f.action(ActionDisable, newSimpleItem(mod), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
-
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
+	return f.action(ActionDisable, newSimpleItem(mod), installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 		l = l.With(
 			slog.String("install", selectedInstallation.Path),
 			slog.String("profile", selectedInstallation.Profile),
@@ -161,14 +182,19 @@ func (f *ficsitCLI) DisableMod(mod string) error {
 
 		profile := f.GetProfile(selectedInstallation.Profile)
 
-		profile.SetModEnabled(mod, false)
+		_ = f.updateState(func() error {
+			profile.SetModEnabled(mod, false)
 
-		err := f.ficsitCli.Profiles.Save()
-		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
-		}
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
+
+		f.markProfileModified(selectedInstallation.Profile)
 
-		installErr := f.apply(l, taskUpdates)
+		installErr := f.apply(l, selectedInstallation, taskUpdates)
 
 		if installErr != nil {
 			l.Error("failed to install", slog.Any("error", installErr))
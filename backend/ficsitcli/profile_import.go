@@ -0,0 +1,219 @@
+package ficsitcli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+)
+
+type ProfileImportPreviewMod struct {
+	ModReference string `json:"modReference"`
+	Name         string `json:"name"`
+	// Version is the locked version, empty if the mod is not locked
+	Version string `json:"version"`
+	// Locked is false for mods of the profile that are not in the lockfile, whose version is resolved on import
+	Locked bool `json:"locked"`
+	// Dependency is true for mods that are only in the lockfile, as a dependency of the profile's mods
+	Dependency bool `json:"dependency"`
+	// Enabled is the profile's state of the mod, and for dependencies, whether an enabled mod depends on them
+	Enabled bool `json:"enabled"`
+	// Size is the download size for the selected install's target, 0 if unknown
+	Size int64 `json:"size"`
+	// GameVersion is the game version constraint of the locked version, empty if unknown
+	GameVersion string `json:"gameVersion"`
+	// Compatible is false if the locked version does not support the game version of the selected install
+	Compatible bool `json:"compatible"`
+	// MissingTarget is true if the locked version has no build for the selected install's target
+	MissingTarget bool `json:"missingTarget"`
+}
+
+type ProfileImportPreview struct {
+	Metadata            *ExportedProfileMetadata  `json:"metadata"`
+	InstallGameVersion  int                       `json:"installGameVersion"`
+	GameVersionMismatch bool                      `json:"gameVersionMismatch"`
+	Target              string                    `json:"target"`
+	Mods                []ProfileImportPreviewMod `json:"mods"`
+	TotalSize           int64                     `json:"totalSize"`
+	// HasIncompatibleMods is true if the lockfile cannot be installed as is on the selected install
+	HasIncompatibleMods bool `json:"hasIncompatibleMods"`
+	// Warnings lists the entries of an SMM2 profile that could not be converted
+	Warnings []string `json:"warnings"`
+}
+
+// PreviewImportProfile parses an exported profile and checks its lockfile against the selected install,
+// without making any changes
+func (f *ficsitCLI) PreviewImportProfile(file string) (*ProfileImportPreview, error) {
+	l := slog.With(slog.String("task", "previewImportProfile"), slog.String("file", file))
+
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		l.Error("no installation selected")
+		return nil, fmt.Errorf("no installation selected")
+	}
+
+	exportedProfile, warnings, err := readExportedProfile(file)
+	if err != nil {
+		l.Error("failed to read exported profile", slog.Any("error", err))
+		return nil, err
+	}
+
+	platform, err := selectedInstallation.GetPlatform(f.ficsitCli)
+	if err != nil {
+		l.Error("failed to get platform", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to get platform: %w", err)
+	}
+
+	preview := &ProfileImportPreview{
+		Metadata: exportedProfile.Metadata,
+		Target:   platform.TargetName,
+		Mods:     []ProfileImportPreviewMod{},
+		Warnings: warnings,
+	}
+
+	installMetadata, ok := f.installationMetadata.Load(selectedInstallation.Path)
+	if ok && installMetadata.Info != nil {
+		preview.InstallGameVersion = installMetadata.Info.Version
+	}
+	if exportedProfile.Metadata != nil && exportedProfile.Metadata.GameVersion != 0 && preview.InstallGameVersion != 0 {
+		preview.GameVersionMismatch = exportedProfile.Metadata.GameVersion != preview.InstallGameVersion
+	}
+
+	var installGameVersion *semver.Version
+	if preview.InstallGameVersion != 0 {
+		v, err := semver.NewVersion(fmt.Sprintf("%d", preview.InstallGameVersion))
+		if err != nil {
+			l.Warn("failed to parse game version", slog.Int("gameVersion", preview.InstallGameVersion), slog.Any("error", err))
+		} else {
+			installGameVersion = &v
+		}
+	}
+
+	installedMods := enabledWithDependencies(exportedProfile.Profile.Mods, &exportedProfile.LockFile)
+
+	modReferences := make(map[string]bool)
+	for modReference := range exportedProfile.Profile.Mods {
+		modReferences[modReference] = true
+	}
+	for modReference := range exportedProfile.LockFile.Mods {
+		modReferences[modReference] = true
+	}
+
+	for modReference := range modReferences {
+		profileMod, inProfile := exportedProfile.Profile.Mods[modReference]
+		lockedMod, locked := exportedProfile.LockFile.Mods[modReference]
+		mod := ProfileImportPreviewMod{
+			ModReference: modReference,
+			Name:         modReference,
+			Version:      lockedMod.Version,
+			Locked:       locked,
+			Dependency:   !inProfile,
+			Enabled:      profileMod.Enabled || (!inProfile && installedMods[modReference]),
+			Compatible:   true,
+		}
+
+		modName, err := f.ficsitCli.Provider.GetModName(context.TODO(), modReference)
+		if err != nil {
+			l.Warn("failed to get mod name", slog.String("mod", modReference), slog.Any("error", err))
+		} else if modName != nil {
+			mod.Name = modName.Name
+		}
+
+		if !locked {
+			preview.Mods = append(preview.Mods, mod)
+			continue
+		}
+
+		// Converted SMM2 lockfiles have no targets, in which case the targets are checked from the mod version
+		if len(lockedMod.Targets) > 0 {
+			if _, ok := lockedMod.Targets[platform.TargetName]; !ok {
+				mod.MissingTarget = true
+			}
+		}
+
+		modVersion, err := f.getModVersion(modReference, lockedMod.Version)
+		if err != nil {
+			l.Warn("failed to get mod version", slog.String("mod", modReference), slog.String("version", lockedMod.Version), slog.Any("error", err))
+		} else if modVersion != nil {
+			mod.GameVersion = modVersion.GameVersion
+			hasTarget := false
+			for _, target := range modVersion.Targets {
+				if string(target.TargetName) == platform.TargetName {
+					mod.Size = target.Size
+					hasTarget = true
+					break
+				}
+			}
+			if len(lockedMod.Targets) == 0 && !hasTarget {
+				mod.MissingTarget = true
+			}
+			if installGameVersion != nil && modVersion.GameVersion != "" {
+				gameVersionConstraint, err := semver.NewConstraint(modVersion.GameVersion)
+				if err != nil {
+					l.Warn("failed to parse game version constraint", slog.String("mod", modReference), slog.String("constraint", modVersion.GameVersion), slog.Any("error", err))
+				} else {
+					mod.Compatible = gameVersionConstraint.Contains(*installGameVersion)
+				}
+			}
+		}
+
+		// Disabled mods are installed too, if an enabled mod depends on them
+		if installedMods[modReference] && (!mod.Compatible || mod.MissingTarget) {
+			preview.HasIncompatibleMods = true
+		}
+		if installedMods[modReference] {
+			preview.TotalSize += mod.Size
+		}
+
+		preview.Mods = append(preview.Mods, mod)
+	}
+
+	sort.Slice(preview.Mods, func(i, j int) bool {
+		return preview.Mods[i].Name < preview.Mods[j].Name
+	})
+
+	return preview, nil
+}
+
+// enabledWithDependencies returns the enabled mods of the profile, and the mods they depend on according to the lockfile
+func enabledWithDependencies(profileMods map[string]cli.ProfileMod, lockfile *resolver.LockFile) map[string]bool {
+	installed := make(map[string]bool)
+	var toVisit []string
+	for modReference, profileMod := range profileMods {
+		if profileMod.Enabled {
+			toVisit = append(toVisit, modReference)
+		}
+	}
+	for len(toVisit) > 0 {
+		modReference := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if installed[modReference] {
+			continue
+		}
+		installed[modReference] = true
+		for dependency := range lockfile.Mods[modReference].Dependencies {
+			if _, ok := lockfile.Mods[dependency]; ok {
+				toVisit = append(toVisit, dependency)
+			}
+		}
+	}
+	return installed
+}
+
+// getModVersion returns the given version of a mod from the provider, or nil if the version does not exist
+func (f *ficsitCLI) getModVersion(modReference string, version string) (*resolver.ModVersion, error) {
+	modVersions, err := f.ficsitCli.Provider.ModVersionsWithDependencies(context.TODO(), modReference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mod versions: %w", err)
+	}
+	for i := range modVersions {
+		if modVersions[i].Version == version {
+			return &modVersions[i], nil
+		}
+	}
+	return nil, nil
+}
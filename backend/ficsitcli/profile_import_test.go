@@ -0,0 +1,54 @@
+package ficsitcli
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+)
+
+func TestEnabledWithDependencies(t *testing.T) {
+	lockfile := &resolver.LockFile{Mods: map[string]resolver.LockedMod{
+		"SML":        {Version: "3.7.0", Dependencies: map[string]string{"FactoryGame": ">=1"}},
+		"ContentLib": {Version: "1.2.0", Dependencies: map[string]string{"SML": "^3.7.0"}},
+		"Pipes":      {Version: "1.0.0", Dependencies: map[string]string{"ContentLib": "^1.0.0"}},
+		"Belts":      {Version: "1.0.0", Dependencies: map[string]string{"SML": "^3.7.0", "Logistics": "^1.0.0"}},
+		"Logistics":  {Version: "1.0.0"},
+	}}
+
+	tests := []struct {
+		name        string
+		profileMods map[string]cli.ProfileMod
+		want        map[string]bool
+	}{
+		{
+			name:        "transitive dependencies",
+			profileMods: map[string]cli.ProfileMod{"Pipes": {Enabled: true}},
+			want:        map[string]bool{"Pipes": true, "ContentLib": true, "SML": true},
+		},
+		{
+			name:        "dependencies of disabled mods",
+			profileMods: map[string]cli.ProfileMod{"Pipes": {Enabled: false}, "Belts": {Enabled: true}},
+			want:        map[string]bool{"Belts": true, "SML": true, "Logistics": true},
+		},
+		{
+			name:        "disabled mod required by an enabled mod",
+			profileMods: map[string]cli.ProfileMod{"ContentLib": {Enabled: false}, "Pipes": {Enabled: true}},
+			want:        map[string]bool{"Pipes": true, "ContentLib": true, "SML": true},
+		},
+		{
+			name:        "enabled mod that is not locked",
+			profileMods: map[string]cli.ProfileMod{"Trains": {Enabled: true}},
+			want:        map[string]bool{"Trains": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := enabledWithDependencies(tt.profileMods, lockfile); !maps.Equal(got, tt.want) {
+				t.Errorf("enabledWithDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
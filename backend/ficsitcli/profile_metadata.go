@@ -0,0 +1,220 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+)
+
+// ProfileMetadata is the SMM-side information about a profile, that ficsit-cli does not store
+type ProfileMetadata struct {
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Author      string            `json:"author,omitempty"`
+	Created     time.Time         `json:"created"`
+	Modified    time.Time         `json:"modified"`
+	GameBranch  common.GameBranch `json:"gameBranch,omitempty"`
+	GameVersion int               `json:"gameVersion,omitempty"`
+	// Revision is incremented on every change to the profile's mods, and is used to detect upstream changes of subscribed profiles
+	Revision     int                  `json:"revision,omitempty"`
+	Subscription *ProfileSubscription `json:"subscription,omitempty"`
+}
+
+type profilesMetadata struct {
+	Profiles map[string]*ProfileMetadata `json:"profiles"`
+}
+
+const profileMetadataFileKey = "profile-metadata-file"
+
+func loadProfilesMetadata() (*profilesMetadata, error) {
+	metadata := &profilesMetadata{}
+	_, err := readSMMData(profileMetadataFileKey, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Profiles == nil {
+		metadata.Profiles = make(map[string]*ProfileMetadata)
+	}
+	return metadata, nil
+}
+
+func (m *profilesMetadata) Save() error {
+	return writeSMMData(profileMetadataFileKey, m)
+}
+
+func (f *ficsitCLI) GetProfilesMetadata() map[string]ProfileMetadata {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	result := make(map[string]ProfileMetadata, len(f.profilesMetadata.Profiles))
+	for name, metadata := range f.profilesMetadata.Profiles {
+		result[name] = metadata.clone()
+	}
+	return result
+}
+
+// clone returns a copy of the metadata that can be read without holding the state lock
+func (m *ProfileMetadata) clone() ProfileMetadata {
+	result := *m
+	result.Tags = slices.Clone(m.Tags)
+	if m.Subscription != nil {
+		result.Subscription = m.Subscription.clone()
+	}
+	return result
+}
+
+func (f *ficsitCLI) GetProfileMetadata(profile string) ProfileMetadata {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	if metadata, ok := f.profilesMetadata.Profiles[profile]; ok {
+		return metadata.clone()
+	}
+	return ProfileMetadata{}
+}
+
+// SetProfileMetadata updates the user-editable metadata of a profile. Creation time, revision and subscription are preserved
+func (f *ficsitCLI) SetProfileMetadata(profile string, metadata ProfileMetadata) error {
+	if f.GetProfile(profile) == nil {
+		return fmt.Errorf("profile not found: %s", profile)
+	}
+
+	metadata.Modified = time.Now().UTC()
+	metadata.Tags = slices.Clone(metadata.Tags)
+	slices.Sort(metadata.Tags)
+	metadata.Tags = slices.Compact(metadata.Tags)
+
+	f.stateMutex.Lock()
+	if existing, ok := f.profilesMetadata.Profiles[profile]; ok {
+		metadata.Created = existing.Created
+		metadata.Revision = existing.Revision
+		metadata.Subscription = existing.Subscription
+	}
+	if metadata.Created.IsZero() {
+		metadata.Created = time.Now().UTC()
+	}
+	f.profilesMetadata.Profiles[profile] = &metadata
+	f.saveProfilesMetadata()
+	f.stateMutex.Unlock()
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+// saveProfilesMetadata saves the profiles metadata. The state lock must be held
+func (f *ficsitCLI) saveProfilesMetadata() {
+	err := f.profilesMetadata.Save()
+	if err != nil {
+		slog.Error("failed to save profile metadata", slog.Any("error", err))
+	}
+}
+
+// initProfileMetadata records a newly created profile, optionally starting from existing metadata
+func (f *ficsitCLI) initProfileMetadata(profile string, base *ProfileMetadata) {
+	metadata := ProfileMetadata{}
+	if base != nil {
+		metadata = *base
+		metadata.Tags = slices.Clone(base.Tags)
+	}
+	now := time.Now().UTC()
+	if metadata.Created.IsZero() {
+		metadata.Created = now
+	}
+	metadata.Modified = now
+
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+	f.profilesMetadata.Profiles[profile] = &metadata
+	f.saveProfilesMetadata()
+}
+
+func (f *ficsitCLI) markProfileModified(profile string) {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	metadata, ok := f.profilesMetadata.Profiles[profile]
+	if !ok {
+		now := time.Now().UTC()
+		f.profilesMetadata.Profiles[profile] = &ProfileMetadata{Created: now, Modified: now}
+		f.saveProfilesMetadata()
+		return
+	}
+	metadata.Modified = time.Now().UTC()
+	metadata.Revision++
+	f.saveProfilesMetadata()
+}
+
+func (f *ficsitCLI) renameProfileMetadata(oldName string, newName string) {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	metadata, ok := f.profilesMetadata.Profiles[oldName]
+	if !ok {
+		return
+	}
+	delete(f.profilesMetadata.Profiles, oldName)
+	f.profilesMetadata.Profiles[newName] = metadata
+	f.saveProfilesMetadata()
+}
+
+func (f *ficsitCLI) deleteProfileMetadata(profile string) {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	if _, ok := f.profilesMetadata.Profiles[profile]; !ok {
+		return
+	}
+	delete(f.profilesMetadata.Profiles, profile)
+	f.saveProfilesMetadata()
+}
+
+type ProfileInstallMismatch struct {
+	Profile            string            `json:"profile"`
+	Install            string            `json:"install"`
+	ProfileBranch      common.GameBranch `json:"profileBranch,omitempty"`
+	InstallBranch      common.GameBranch `json:"installBranch,omitempty"`
+	ProfileGameVersion int               `json:"profileGameVersion,omitempty"`
+	InstallGameVersion int               `json:"installGameVersion,omitempty"`
+}
+
+// GetProfileInstallMismatch returns the differences between the branch and game version the profile was made for
+// and the ones of the installation. Returns nil if the profile does not specify them, or they match
+func (f *ficsitCLI) GetProfileInstallMismatch(profile string, install string) *ProfileInstallMismatch {
+	metadata := f.GetProfileMetadata(profile)
+	installMetadata, ok := f.installationMetadata.Load(install)
+	if !ok || installMetadata.Info == nil {
+		return nil
+	}
+
+	mismatch := &ProfileInstallMismatch{
+		Profile: profile,
+		Install: install,
+	}
+	found := false
+	if metadata.GameBranch != "" && metadata.GameBranch != installMetadata.Info.Branch {
+		mismatch.ProfileBranch = metadata.GameBranch
+		mismatch.InstallBranch = installMetadata.Info.Branch
+		found = true
+	}
+	if metadata.GameVersion != 0 && installMetadata.Info.Version != 0 && metadata.GameVersion != installMetadata.Info.Version {
+		mismatch.ProfileGameVersion = metadata.GameVersion
+		mismatch.InstallGameVersion = installMetadata.Info.Version
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return mismatch
+}
+
+func (f *ficsitCLI) GetSelectedProfileInstallMismatch() *ProfileInstallMismatch {
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return nil
+	}
+	return f.GetProfileInstallMismatch(selectedInstallation.Profile, selectedInstallation.Path)
+}
@@ -0,0 +1,506 @@
+package ficsitcli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+)
+
+// ProfileSubscription links a profile to an upstream exported profile, either a file path or an HTTP URL
+type ProfileSubscription struct {
+	Source     string `json:"source"`
+	AutoUpdate bool   `json:"autoUpdate"`
+	// Revision identifies the upstream revision the profile was last updated to
+	Revision string `json:"revision"`
+	// LockedVersions are the locked versions of that upstream revision, used to show what changed since
+	LockedVersions map[string]string `json:"lockedVersions"`
+	LastChecked    time.Time         `json:"lastChecked"`
+	LastError      string            `json:"lastError,omitempty"`
+}
+
+type ProfileSubscriptionChangeType string
+
+const (
+	ProfileSubscriptionChangeAdded             ProfileSubscriptionChangeType = "added"
+	ProfileSubscriptionChangeRemoved           ProfileSubscriptionChangeType = "removed"
+	ProfileSubscriptionChangeEnabled           ProfileSubscriptionChangeType = "enabled"
+	ProfileSubscriptionChangeDisabled          ProfileSubscriptionChangeType = "disabled"
+	ProfileSubscriptionChangeConstraintChanged ProfileSubscriptionChangeType = "constraintChanged"
+	ProfileSubscriptionChangeVersionChanged    ProfileSubscriptionChangeType = "versionChanged"
+)
+
+type ProfileSubscriptionChange struct {
+	ModReference string                        `json:"modReference"`
+	Type         ProfileSubscriptionChangeType `json:"type"`
+	From         string                        `json:"from,omitempty"`
+	To           string                        `json:"to,omitempty"`
+}
+
+// ProfileSubscriptionUpdate is a new upstream revision that has not been applied to the profile yet
+type ProfileSubscriptionUpdate struct {
+	Profile  string                      `json:"profile"`
+	Revision string                      `json:"revision"`
+	Changes  []ProfileSubscriptionChange `json:"changes"`
+	Warnings []string                    `json:"warnings"`
+}
+
+const (
+	profileSubscriptionCheckInterval = 15 * time.Minute
+	profileSubscriptionFetchTimeout  = 30 * time.Second
+)
+
+func isHTTPSource(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func fetchProfileSubscriptionSource(source string) ([]byte, error) {
+	if !isHTTPSource(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", source, err)
+		}
+		return data, nil
+	}
+
+	client := http.Client{Timeout: profileSubscriptionFetchTimeout}
+	response, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", source, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", source, response.Status)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
+
+// fetchProfileSubscription downloads and parses the upstream profile, and returns its revision.
+// The revision is the one stored in the profile metadata if present, otherwise the hash of the file
+func fetchProfileSubscription(source string) (*ExportedProfile, string, []string, error) {
+	data, err := fetchProfileSubscriptionSource(source)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	name := strings.TrimSuffix(path.Base(strings.ReplaceAll(source, "\\", "/")), ".smmprofile")
+	exportedProfile, warnings, err := parseExportedProfile(name, data)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if exportedProfile.Metadata != nil && exportedProfile.Metadata.Revision != 0 {
+		return exportedProfile, fmt.Sprintf("r%d", exportedProfile.Metadata.Revision), warnings, nil
+	}
+	hash := sha256.Sum256(data)
+	return exportedProfile, hex.EncodeToString(hash[:]), warnings, nil
+}
+
+func lockedVersions(exportedProfile *ExportedProfile) map[string]string {
+	versions := make(map[string]string, len(exportedProfile.LockFile.Mods))
+	for modReference, lockedMod := range exportedProfile.LockFile.Mods {
+		versions[modReference] = lockedMod.Version
+	}
+	return versions
+}
+
+// profileSubscriptionChanges lists the differences between the local profile and the upstream one
+func profileSubscriptionChanges(localMods map[string]cli.ProfileMod, localLockedVersions map[string]string, upstream *ExportedProfile) []ProfileSubscriptionChange {
+	changes := []ProfileSubscriptionChange{}
+
+	for modReference, upstreamMod := range upstream.Profile.Mods {
+		localMod, ok := localMods[modReference]
+		if !ok {
+			changes = append(changes, ProfileSubscriptionChange{
+				ModReference: modReference,
+				Type:         ProfileSubscriptionChangeAdded,
+				To:           upstreamMod.Version,
+			})
+			continue
+		}
+		if localMod.Version != upstreamMod.Version {
+			changes = append(changes, ProfileSubscriptionChange{
+				ModReference: modReference,
+				Type:         ProfileSubscriptionChangeConstraintChanged,
+				From:         localMod.Version,
+				To:           upstreamMod.Version,
+			})
+		}
+		if localMod.Enabled != upstreamMod.Enabled {
+			changeType := ProfileSubscriptionChangeDisabled
+			if upstreamMod.Enabled {
+				changeType = ProfileSubscriptionChangeEnabled
+			}
+			changes = append(changes, ProfileSubscriptionChange{
+				ModReference: modReference,
+				Type:         changeType,
+			})
+		}
+	}
+
+	for modReference, localMod := range localMods {
+		if _, ok := upstream.Profile.Mods[modReference]; !ok {
+			changes = append(changes, ProfileSubscriptionChange{
+				ModReference: modReference,
+				Type:         ProfileSubscriptionChangeRemoved,
+				From:         localMod.Version,
+			})
+		}
+	}
+
+	for modReference, lockedMod := range upstream.LockFile.Mods {
+		localVersion, ok := localLockedVersions[modReference]
+		if ok && localVersion != lockedMod.Version {
+			changes = append(changes, ProfileSubscriptionChange{
+				ModReference: modReference,
+				Type:         ProfileSubscriptionChangeVersionChanged,
+				From:         localVersion,
+				To:           lockedMod.Version,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ModReference != changes[j].ModReference {
+			return changes[i].ModReference < changes[j].ModReference
+		}
+		return changes[i].Type < changes[j].Type
+	})
+
+	return changes
+}
+
+// SubscribeProfile makes a profile follow an upstream exported profile. The profile is considered up to date
+// with the upstream revision at the time of subscribing, and is not changed until a later revision is applied
+func (f *ficsitCLI) SubscribeProfile(profile string, source string, autoUpdate bool) error {
+	l := slog.With(slog.String("task", "subscribeProfile"), slog.String("profile", profile), slog.String("source", source))
+
+	if f.GetProfile(profile) == nil {
+		return fmt.Errorf("profile not found: %s", profile)
+	}
+
+	upstream, revision, _, err := fetchProfileSubscription(source)
+	if err != nil {
+		l.Error("failed to fetch profile source", slog.Any("error", err))
+		return fmt.Errorf("failed to fetch profile source: %w", err)
+	}
+
+	f.stateMutex.Lock()
+	metadata, ok := f.profilesMetadata.Profiles[profile]
+	if !ok {
+		now := time.Now().UTC()
+		metadata = &ProfileMetadata{Created: now, Modified: now}
+		f.profilesMetadata.Profiles[profile] = metadata
+	}
+	metadata.Subscription = &ProfileSubscription{
+		Source:         source,
+		AutoUpdate:     autoUpdate,
+		Revision:       revision,
+		LockedVersions: lockedVersions(upstream),
+		LastChecked:    time.Now().UTC(),
+	}
+	f.saveProfilesMetadata()
+	f.stateMutex.Unlock()
+
+	f.subscriptionUpdates.Delete(profile)
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+func (f *ficsitCLI) UnsubscribeProfile(profile string) error {
+	f.stateMutex.Lock()
+	metadata, ok := f.profilesMetadata.Profiles[profile]
+	if !ok || metadata.Subscription == nil {
+		f.stateMutex.Unlock()
+		return fmt.Errorf("profile is not subscribed: %s", profile)
+	}
+
+	metadata.Subscription = nil
+	f.saveProfilesMetadata()
+	f.stateMutex.Unlock()
+
+	f.subscriptionUpdates.Delete(profile)
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+func (f *ficsitCLI) SetProfileSubscriptionAutoUpdate(profile string, autoUpdate bool) error {
+	f.stateMutex.Lock()
+	metadata, ok := f.profilesMetadata.Profiles[profile]
+	if !ok || metadata.Subscription == nil {
+		f.stateMutex.Unlock()
+		return fmt.Errorf("profile is not subscribed: %s", profile)
+	}
+
+	metadata.Subscription.AutoUpdate = autoUpdate
+	f.saveProfilesMetadata()
+	f.stateMutex.Unlock()
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+// profileSubscription returns a copy of the subscription of the profile, or nil if it is not subscribed
+func (f *ficsitCLI) profileSubscription(profile string) *ProfileSubscription {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	metadata, ok := f.profilesMetadata.Profiles[profile]
+	if !ok || metadata.Subscription == nil {
+		return nil
+	}
+	return metadata.Subscription.clone()
+}
+
+// updateProfileSubscription changes the subscription of the profile and saves it,
+// unless the profile was unsubscribed or subscribed to another source in the meantime
+func (f *ficsitCLI) updateProfileSubscription(profile string, source string, update func(*ProfileMetadata, *ProfileSubscription)) {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	metadata, ok := f.profilesMetadata.Profiles[profile]
+	if !ok || metadata.Subscription == nil || metadata.Subscription.Source != source {
+		return
+	}
+	update(metadata, metadata.Subscription)
+	f.saveProfilesMetadata()
+}
+
+func (s *ProfileSubscription) clone() *ProfileSubscription {
+	result := *s
+	result.LockedVersions = maps.Clone(s.LockedVersions)
+	return &result
+}
+
+// profileInstalls returns the installs using the profile, the selected install first
+func (f *ficsitCLI) profileInstalls(profile string) []*cli.Installation {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	var installs []*cli.Installation
+	selectedInstallation := f.ficsitCli.Installations.SelectedInstallation
+	for _, installation := range f.ficsitCli.Installations.Installations {
+		if installation.Profile != profile {
+			continue
+		}
+		if installation.Path == selectedInstallation {
+			installs = append([]*cli.Installation{installation}, installs...)
+		} else {
+			installs = append(installs, installation)
+		}
+	}
+	return installs
+}
+
+// profileLockedVersions returns the locked versions of an install using the profile, preferring the selected install.
+// Returns nil if no install using the profile has a lockfile
+func (f *ficsitCLI) profileLockedVersions(profile string) map[string]string {
+	for _, installation := range f.profileInstalls(profile) {
+		lockfile, err := installation.LockFile(f.ficsitCli)
+		if err != nil || lockfile == nil {
+			continue
+		}
+		versions := make(map[string]string, len(lockfile.Mods))
+		for modReference, lockedMod := range lockfile.Mods {
+			versions[modReference] = lockedMod.Version
+		}
+		return versions
+	}
+	return nil
+}
+
+func (f *ficsitCLI) GetProfileSubscriptionUpdates() map[string]ProfileSubscriptionUpdate {
+	updates := make(map[string]ProfileSubscriptionUpdate)
+	f.subscriptionUpdates.Range(func(profile string, update *ProfileSubscriptionUpdate) bool {
+		updates[profile] = *update
+		return true
+	})
+	return updates
+}
+
+// CheckProfileSubscriptions polls the sources of all subscribed profiles,
+// and updates the ones that opted in to automatic updates
+func (f *ficsitCLI) CheckProfileSubscriptions() map[string]ProfileSubscriptionUpdate {
+	for _, profile := range f.GetProfiles() {
+		update := f.checkProfileSubscription(profile)
+		if update == nil {
+			continue
+		}
+		subscription := f.profileSubscription(profile)
+		if subscription == nil || !subscription.AutoUpdate {
+			continue
+		}
+		gameRunning := slices.ContainsFunc(f.profileInstalls(profile), func(installation *cli.Installation) bool {
+			return f.isGameRunningOn(installation.Path)
+		})
+		if gameRunning {
+			slog.Info("game is running on an install using the profile, not updating subscribed profile", slog.String("profile", profile))
+			continue
+		}
+		err := f.UpdateSubscribedProfile(profile, update.Revision)
+		if err != nil {
+			slog.Error("failed to update subscribed profile", slog.String("profile", profile), slog.Any("error", err))
+		}
+	}
+
+	f.EmitGlobals()
+
+	return f.GetProfileSubscriptionUpdates()
+}
+
+// checkProfileSubscription fetches the source of a subscribed profile, and records whether it has a new revision.
+// Locked version changes are against the lockfile of an install using the profile, since that is what is installed
+func (f *ficsitCLI) checkProfileSubscription(profile string) *ProfileSubscriptionUpdate {
+	subscription := f.profileSubscription(profile)
+	if subscription == nil {
+		return nil
+	}
+	l := slog.With(slog.String("task", "checkProfileSubscription"), slog.String("profile", profile), slog.String("source", subscription.Source))
+
+	upstream, revision, warnings, err := fetchProfileSubscription(subscription.Source)
+	f.updateProfileSubscription(profile, subscription.Source, func(_ *ProfileMetadata, current *ProfileSubscription) {
+		current.LastChecked = time.Now().UTC()
+		current.LastError = ""
+		if err != nil {
+			current.LastError = err.Error()
+		}
+	})
+	if err != nil {
+		l.Warn("failed to fetch profile source", slog.Any("error", err))
+		return nil
+	}
+
+	if revision == subscription.Revision {
+		f.subscriptionUpdates.Delete(profile)
+		return nil
+	}
+
+	var localMods map[string]cli.ProfileMod
+	if localProfile := f.GetProfile(profile); localProfile != nil {
+		f.stateMutex.Lock()
+		localMods = maps.Clone(localProfile.Mods)
+		f.stateMutex.Unlock()
+	}
+
+	localLockedVersions := f.profileLockedVersions(profile)
+	if localLockedVersions == nil {
+		localLockedVersions = subscription.LockedVersions
+	}
+
+	update := &ProfileSubscriptionUpdate{
+		Profile:  profile,
+		Revision: revision,
+		Changes:  profileSubscriptionChanges(localMods, localLockedVersions, upstream),
+		Warnings: warnings,
+	}
+	f.subscriptionUpdates.Store(profile, update)
+
+	l.Info("subscribed profile has a new revision", slog.String("revision", revision), slog.Int("changes", len(update.Changes)))
+
+	return update
+}
+
+// UpdateSubscribedProfile replaces the mods of the profile with the ones of the previewed upstream revision.
+// If the source has changed since the preview, nothing is changed, so what is applied is always what was previewed.
+// Installs using the profile get the upstream lockfile, and the selected install is applied if it uses the profile
+func (f *ficsitCLI) UpdateSubscribedProfile(profile string, revision string) error {
+	return f.action(ActionUpdateSubscription, newSimpleItem(profile), profileScope(profile), func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		subscription := f.profileSubscription(profile)
+		if subscription == nil {
+			return fmt.Errorf("profile is not subscribed: %s", profile)
+		}
+
+		localProfile := f.GetProfile(profile)
+		if localProfile == nil {
+			return fmt.Errorf("profile not found: %s", profile)
+		}
+
+		upstream, fetchedRevision, warnings, err := fetchProfileSubscription(subscription.Source)
+		if err != nil {
+			l.Error("failed to fetch profile source", slog.Any("error", err))
+			return fmt.Errorf("failed to fetch profile source: %w", err)
+		}
+		if fetchedRevision != revision {
+			l.Warn("profile source changed since it was checked", slog.String("expected", revision), slog.String("fetched", fetchedRevision))
+			return fmt.Errorf("the profile source changed to revision %s since revision %s was checked, check for updates again", fetchedRevision, revision)
+		}
+		for _, warning := range warnings {
+			l.Warn("profile conversion warning", slog.String("warning", warning))
+		}
+
+		_ = f.updateState(func() error {
+			localProfile.Mods = maps.Clone(upstream.Profile.Mods)
+			if localProfile.Mods == nil {
+				localProfile.Mods = make(map[string]cli.ProfileMod)
+			}
+
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
+
+		// The lockfile is only a hint for the resolver, so the upstream versions are kept as long as they are compatible
+		for _, installation := range f.profileInstalls(profile) {
+			err := installation.WriteLockFile(f.ficsitCli, &upstream.LockFile)
+			if err != nil {
+				l.Warn("failed to write lockfile", slog.String("install", installation.Path), slog.Any("error", err))
+			}
+		}
+
+		f.updateProfileSubscription(profile, subscription.Source, func(metadata *ProfileMetadata, current *ProfileSubscription) {
+			metadata.Modified = time.Now().UTC()
+			current.Revision = revision
+			current.LockedVersions = lockedVersions(upstream)
+			current.LastError = ""
+		})
+		f.subscriptionUpdates.Delete(profile)
+
+		f.EmitGlobals()
+		f.EmitModsChange()
+
+		selectedInstallation := f.GetSelectedInstall()
+		if selectedInstallation == nil || selectedInstallation.Profile != profile {
+			return nil
+		}
+
+		installErr := f.apply(l, selectedInstallation, taskChannel)
+		if installErr != nil {
+			l.Error("failed to apply subscribed profile", slog.Any("error", installErr))
+			return installErr
+		}
+		return nil
+	})
+}
+
+func (f *ficsitCLI) StartProfileSubscriptionWatcher() {
+	go func() {
+		f.CheckProfileSubscriptions()
+
+		ticker := time.NewTicker(profileSubscriptionCheckInterval)
+		for range ticker.C {
+			f.CheckProfileSubscriptions()
+		}
+	}()
+}
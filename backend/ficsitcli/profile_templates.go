@@ -0,0 +1,175 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	appCommon "github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
+)
+
+type ProfileTemplate struct {
+	Name string                    `json:"name"`
+	Mods map[string]cli.ProfileMod `json:"mods"`
+}
+
+type profileTemplates struct {
+	Templates map[string]*ProfileTemplate `json:"templates"`
+}
+
+const profileTemplatesFileKey = "profile-templates-file"
+
+func loadProfileTemplates() (*profileTemplates, error) {
+	templates := &profileTemplates{}
+	_, err := readSMMData(profileTemplatesFileKey, templates)
+	if err != nil {
+		return nil, err
+	}
+	if templates.Templates == nil {
+		templates.Templates = make(map[string]*ProfileTemplate)
+	}
+	return templates, nil
+}
+
+func (t *profileTemplates) Save() error {
+	return writeSMMData(profileTemplatesFileKey, t)
+}
+
+func (f *ficsitCLI) GetProfileTemplates() []string {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	templateNames := make([]string, 0, len(f.profileTemplates.Templates))
+	for k := range f.profileTemplates.Templates {
+		templateNames = append(templateNames, k)
+	}
+	sort.Strings(templateNames)
+	return templateNames
+}
+
+// GetProfileTemplate returns a copy of the template, so it can be used while templates are changed
+func (f *ficsitCLI) GetProfileTemplate(name string) *ProfileTemplate {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	template, ok := f.profileTemplates.Templates[name]
+	if !ok {
+		return nil
+	}
+	return &ProfileTemplate{
+		Name: template.Name,
+		Mods: maps.Clone(template.Mods),
+	}
+}
+
+// SaveProfileAsTemplate stores the mods of a profile as a template, replacing any existing template with the same name
+func (f *ficsitCLI) SaveProfileAsTemplate(profileName string, templateName string) error {
+	l := slog.With(slog.String("task", "saveProfileAsTemplate"), slog.String("profile", profileName), slog.String("template", templateName))
+
+	profile := f.copyProfile(profileName)
+	if profile == nil {
+		return fmt.Errorf("profile not found: %s", profileName)
+	}
+
+	err := f.updateState(func() error {
+		f.profileTemplates.Templates[templateName] = &ProfileTemplate{
+			Name: templateName,
+			Mods: profile.Mods,
+		}
+
+		err := f.profileTemplates.Save()
+		if err != nil {
+			l.Error("failed to save profile templates", slog.Any("error", err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.emitProfileTemplates()
+
+	return nil
+}
+
+func (f *ficsitCLI) DeleteProfileTemplate(name string) error {
+	l := slog.With(slog.String("task", "deleteProfileTemplate"), slog.String("template", name))
+
+	err := f.updateState(func() error {
+		if _, ok := f.profileTemplates.Templates[name]; !ok {
+			return fmt.Errorf("template not found: %s", name)
+		}
+
+		delete(f.profileTemplates.Templates, name)
+
+		err := f.profileTemplates.Save()
+		if err != nil {
+			l.Error("failed to save profile templates", slog.Any("error", err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.emitProfileTemplates()
+
+	return nil
+}
+
+// AddProfileFromTemplates creates a new profile containing the mods of all the given templates.
+// Templates are applied in order, so later templates take precedence when their constraints differ.
+func (f *ficsitCLI) AddProfileFromTemplates(name string, templates []string) error {
+	l := slog.With(slog.String("task", "addProfileFromTemplates"), slog.String("profile", name))
+
+	mods := make(map[string]cli.ProfileMod)
+	for _, templateName := range templates {
+		template := f.GetProfileTemplate(templateName)
+		if template == nil {
+			return fmt.Errorf("template not found: %s", templateName)
+		}
+		var err error
+		mods, err = mergeProfileMods(mods, template.Mods, MergeStrategyReplace)
+		if err != nil {
+			return fmt.Errorf("failed to apply template %s: %w", templateName, err)
+		}
+	}
+
+	err := f.updateState(func() error {
+		profile, err := f.ficsitCli.Profiles.AddProfile(name)
+		if err != nil {
+			l.Error("failed to add profile", slog.Any("error", err))
+			return fmt.Errorf("failed to add profile: %s: %w", name, err)
+		}
+
+		profile.Mods = mods
+
+		err = f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.initProfileMetadata(name, nil)
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+func (f *ficsitCLI) emitProfileTemplates() {
+	if appCommon.AppContext == nil {
+		return
+	}
+	wailsRuntime.EventsEmit(appCommon.AppContext, "profileTemplates", f.GetProfileTemplates())
+}
@@ -1,52 +1,62 @@
 package ficsitcli
 
 import (
-	"archive/zip"
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
 	"github.com/satisfactorymodding/ficsit-cli/cli"
 	resolver "github.com/satisfactorymodding/ficsit-resolver"
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 
 	appCommon "github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
 )
 
 func (f *ficsitCLI) SetProfile(profile string) error {
-	return f.action(ActionSelectProfile, newSimpleItem(profile), func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
-		if selectedInstallation == nil {
-			l.Error("no installation selected")
-			return fmt.Errorf("no installation selected")
-		}
+	scope := installProfileScope(selectedInstallation).with(profileScope(profile))
+	return f.action(ActionSelectProfile, newSimpleItem(profile), scope, func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
 		if selectedInstallation.Profile == profile {
 			return nil
 		}
 
-		err := selectedInstallation.SetProfile(f.ficsitCli, profile)
-		if err != nil {
-			l.Error("failed to set profile", slog.Any("error", err))
-			return fmt.Errorf("failed to set profile: %w", err)
-		}
+		err := f.updateState(func() error {
+			err := selectedInstallation.SetProfile(f.ficsitCli, profile)
+			if err != nil {
+				l.Error("failed to set profile", slog.Any("error", err))
+				return fmt.Errorf("failed to set profile: %w", err)
+			}
 
-		err = f.ficsitCli.Installations.Save()
+			err = f.ficsitCli.Installations.Save()
+			if err != nil {
+				l.Error("failed to save installations", slog.Any("error", err))
+			}
+			return nil
+		})
 		if err != nil {
-			l.Error("failed to save installations", slog.Any("error", err))
+			return err
 		}
 
 		f.EmitGlobals()
 		f.EmitModsChange()
 
 		if settings.Settings.QueueAutoStart {
-			installErr := f.apply(l, taskChannel)
+			installErr := f.apply(l, selectedInstallation, taskChannel)
 
 			if installErr != nil {
 				l.Error("failed to validate installation", slog.Any("error", installErr))
@@ -66,6 +76,9 @@ func (f *ficsitCLI) GetSelectedProfile() *string {
 }
 
 func (f *ficsitCLI) GetProfiles() []string {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
 	profileNames := make([]string, 0, len(f.ficsitCli.Profiles.Profiles))
 	for k := range f.ficsitCli.Profiles.Profiles {
 		profileNames = append(profileNames, k)
@@ -78,6 +91,23 @@ func (f *ficsitCLI) GetProfile(profile string) *cli.Profile {
 	return f.ficsitCli.Profiles.GetProfile(profile)
 }
 
+// copyProfile returns a copy of the profile made while holding the state lock, so it can be read,
+// for example resolved, while actions change the profile. Returns nil if the profile does not exist
+func (f *ficsitCLI) copyProfile(profile string) *cli.Profile {
+	f.stateMutex.Lock()
+	defer f.stateMutex.Unlock()
+
+	original := f.ficsitCli.Profiles.GetProfile(profile)
+	if original == nil {
+		return nil
+	}
+	return &cli.Profile{
+		Name:            original.Name,
+		Mods:            maps.Clone(original.Mods),
+		RequiredTargets: slices.Clone(original.RequiredTargets),
+	}
+}
+
 func (f *ficsitCLI) GetFallbackProfile() string {
 	fallbackProfile := cli.DefaultProfileName
 	if f.ficsitCli.Profiles.GetProfile(fallbackProfile) == nil {
@@ -108,17 +138,25 @@ func (f *ficsitCLI) GetFallbackProfileExcept(profile string) string {
 func (f *ficsitCLI) AddProfile(name string) error {
 	l := slog.With(slog.String("task", "addProfile"), slog.String("profile", name))
 
-	_, err := f.ficsitCli.Profiles.AddProfile(name)
-	if err != nil {
-		l.Error("failed to add profile", slog.Any("error", err))
-		return fmt.Errorf("failed to add profile: %s: %w", name, err)
-	}
+	err := f.updateState(func() error {
+		_, err := f.ficsitCli.Profiles.AddProfile(name)
+		if err != nil {
+			l.Error("failed to add profile", slog.Any("error", err))
+			return fmt.Errorf("failed to add profile: %s: %w", name, err)
+		}
 
-	err = f.ficsitCli.Profiles.Save()
+		err = f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
 	if err != nil {
-		l.Error("failed to save profile", slog.Any("error", err))
+		return err
 	}
 
+	f.initProfileMetadata(name, nil)
+
 	f.EmitGlobals()
 
 	return nil
@@ -127,23 +165,40 @@ func (f *ficsitCLI) AddProfile(name string) error {
 func (f *ficsitCLI) RenameProfile(oldName string, newName string) error {
 	l := slog.With(slog.String("task", "renameProfile"), slog.String("oldName", oldName), slog.String("newName", newName))
 
-	err := f.ficsitCli.Profiles.RenameProfile(f.ficsitCli, oldName, newName)
+	// Renaming changes the profile of the installs using it
+	_, unlock, err := f.lockScope(profileScope(oldName).with(profileScope(newName)))
 	if err != nil {
-		l.Error("failed to rename profile", slog.Any("error", err))
-		return fmt.Errorf("failed to rename profile: %s -> %s: %w", oldName, newName, err)
+		return err
 	}
+	defer unlock()
 
-	err = f.ficsitCli.Profiles.Save()
-	if err != nil {
-		l.Error("failed to save profile", slog.Any("error", err))
-	}
+	err = f.updateState(func() error {
+		err := f.ficsitCli.Profiles.RenameProfile(f.ficsitCli, oldName, newName)
+		if err != nil {
+			l.Error("failed to rename profile", slog.Any("error", err))
+			return fmt.Errorf("failed to rename profile: %s -> %s: %w", oldName, newName, err)
+		}
+
+		err = f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
 
-	// Installs using the old name will be updated
-	err = f.ficsitCli.Installations.Save()
+		// Installs using the old name will be updated
+		err = f.ficsitCli.Installations.Save()
+		if err != nil {
+			l.Error("failed to save installations", slog.Any("error", err))
+		}
+		return nil
+	})
 	if err != nil {
-		l.Error("failed to save installations", slog.Any("error", err))
+		return err
 	}
 
+	f.renameProfileMetadata(oldName, newName)
+	f.subscriptionUpdates.Delete(oldName)
+	settings.Settings.RenameIgnoreRulesProfile(oldName, newName)
+
 	f.EmitGlobals()
 
 	return nil
@@ -152,36 +207,194 @@ func (f *ficsitCLI) RenameProfile(oldName string, newName string) error {
 func (f *ficsitCLI) DeleteProfile(name string) error {
 	l := slog.With(slog.String("task", "deleteProfile"), slog.String("profile", name))
 
-	// ficsit-cli always sets installs that use the deleted profile to Default, which might not exist
+	// Deleting changes the profile of the installs using it
 	fallbackProfile := f.GetFallbackProfileExcept(name)
-	for _, installation := range f.ficsitCli.Installations.Installations {
-		if installation.Profile == name {
-			_ = installation.SetProfile(f.ficsitCli, fallbackProfile)
+	_, unlock, err := f.lockScope(profileScope(name).with(profileScope(fallbackProfile)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = f.updateState(func() error {
+		// ficsit-cli always sets installs that use the deleted profile to Default, which might not exist
+		for _, installation := range f.ficsitCli.Installations.Installations {
+			if installation.Profile == name {
+				_ = installation.SetProfile(f.ficsitCli, fallbackProfile)
+			}
 		}
+
+		err := f.ficsitCli.Profiles.DeleteProfile(name)
+		if err != nil {
+			l.Error("failed to delete profile", slog.Any("error", err))
+			return fmt.Errorf("failed to delete profile: %s: %w", name, err)
+		}
+
+		err = f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+
+		// Installs using the profile will be updated
+		err = f.ficsitCli.Installations.Save()
+		if err != nil {
+			l.Error("failed to save installations", slog.Any("error", err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	f.deleteProfileMetadata(name)
+	f.subscriptionUpdates.Delete(name)
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+func (f *ficsitCLI) CloneProfile(src string, dst string) error {
+	l := slog.With(slog.String("task", "cloneProfile"), slog.String("src", src), slog.String("dst", dst))
+
+	srcProfile := f.copyProfile(src)
+	if srcProfile == nil {
+		return fmt.Errorf("profile not found: %s", src)
 	}
 
-	err := f.ficsitCli.Profiles.DeleteProfile(name)
+	err := f.updateState(func() error {
+		profile, err := f.ficsitCli.Profiles.AddProfile(dst)
+		if err != nil {
+			l.Error("failed to add profile", slog.Any("error", err))
+			return fmt.Errorf("failed to add profile: %s: %w", dst, err)
+		}
+
+		profile.Mods = srcProfile.Mods
+		profile.RequiredTargets = srcProfile.RequiredTargets
+
+		err = f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
 	if err != nil {
-		l.Error("failed to delete profile", slog.Any("error", err))
-		return fmt.Errorf("failed to delete profile: %s: %w", name, err)
+		return err
+	}
+
+	srcMetadata := f.GetProfileMetadata(src)
+	srcMetadata.Created = time.Time{}
+	srcMetadata.Subscription = nil
+	f.initProfileMetadata(dst, &srcMetadata)
+
+	f.EmitGlobals()
+
+	return nil
+}
+
+func (f *ficsitCLI) MergeProfiles(into string, from string, strategy MergeStrategy) error {
+	l := slog.With(slog.String("task", "mergeProfiles"), slog.String("into", into), slog.String("from", from), slog.String("strategy", string(strategy)))
+
+	intoProfile := f.GetProfile(into)
+	if intoProfile == nil {
+		return fmt.Errorf("profile not found: %s", into)
+	}
+	fromProfile := f.GetProfile(from)
+	if fromProfile == nil {
+		return fmt.Errorf("profile not found: %s", from)
 	}
 
-	err = f.ficsitCli.Profiles.Save()
+	// The merged profile must not change while it is being applied
+	_, unlock, err := f.lockScope(profileScope(into))
 	if err != nil {
-		l.Error("failed to save profile", slog.Any("error", err))
+		return err
 	}
+	defer unlock()
 
-	// Installs using the profile will be updated
-	err = f.ficsitCli.Installations.Save()
+	err = f.updateState(func() error {
+		mergedMods, err := mergeProfileMods(intoProfile.Mods, fromProfile.Mods, strategy)
+		if err != nil {
+			l.Error("failed to merge profiles", slog.Any("error", err))
+			return fmt.Errorf("failed to merge profile %s into %s: %w", from, into, err)
+		}
+		intoProfile.Mods = mergedMods
+
+		err = f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
 	if err != nil {
-		l.Error("failed to save installations", slog.Any("error", err))
+		return err
 	}
 
+	f.markProfileModified(into)
+
 	f.EmitGlobals()
+	f.EmitModsChange()
 
 	return nil
 }
 
+// mergeProfileMods returns a new mod map with the mods of from added to into.
+// Mods present in both are resolved according to the strategy, and are enabled if enabled in either.
+func mergeProfileMods(into map[string]cli.ProfileMod, from map[string]cli.ProfileMod, strategy MergeStrategy) (map[string]cli.ProfileMod, error) {
+	result := maps.Clone(into)
+	if result == nil {
+		result = make(map[string]cli.ProfileMod)
+	}
+
+	var conflicts []string
+	for modReference, fromMod := range from {
+		intoMod, ok := result[modReference]
+		if !ok {
+			result[modReference] = fromMod
+			continue
+		}
+
+		version := intoMod.Version
+		if intoMod.Version != fromMod.Version {
+			switch strategy {
+			case MergeStrategyKeep:
+			case MergeStrategyReplace:
+				version = fromMod.Version
+			case MergeStrategyIntersect:
+				intoConstraint, err := semver.NewConstraint(intoMod.Version)
+				if err != nil {
+					return nil, fmt.Errorf("invalid constraint %s for %s: %w", intoMod.Version, modReference, err)
+				}
+				fromConstraint, err := semver.NewConstraint(fromMod.Version)
+				if err != nil {
+					return nil, fmt.Errorf("invalid constraint %s for %s: %w", fromMod.Version, modReference, err)
+				}
+				intersection := intoConstraint.Intersect(fromConstraint)
+				if intersection.IsEmpty() {
+					conflicts = append(conflicts, fmt.Sprintf("%s (%s, %s)", modReference, intoMod.Version, fromMod.Version))
+					continue
+				}
+				version = intersection.String()
+			case MergeStrategyFail:
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s, %s)", modReference, intoMod.Version, fromMod.Version))
+				continue
+			default:
+				return nil, fmt.Errorf("unknown merge strategy: %s", strategy)
+			}
+		}
+
+		result[modReference] = cli.ProfileMod{
+			Version: version,
+			Enabled: intoMod.Enabled || fromMod.Enabled,
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf("conflicting constraints: %s", strings.Join(conflicts, ", "))
+	}
+
+	return result, nil
+}
+
 type ExportedProfile struct {
 	Profile  cli.Profile              `json:"profile"`
 	LockFile resolver.LockFile        `json:"lockfile"`
@@ -189,7 +402,31 @@ type ExportedProfile struct {
 }
 
 type ExportedProfileMetadata struct {
-	GameVersion int `json:"gameVersion"`
+	GameVersion int               `json:"gameVersion"`
+	GameBranch  common.GameBranch `json:"gameBranch,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Author      string            `json:"author,omitempty"`
+	Created     time.Time         `json:"created,omitempty"`
+	Modified    time.Time         `json:"modified,omitempty"`
+	Revision    int               `json:"revision,omitempty"`
+}
+
+// profileMetadata converts the exported metadata to the metadata stored for an imported profile
+func (m *ExportedProfileMetadata) profileMetadata() *ProfileMetadata {
+	if m == nil {
+		return nil
+	}
+	return &ProfileMetadata{
+		Description: m.Description,
+		Tags:        m.Tags,
+		Author:      m.Author,
+		Created:     m.Created,
+		Modified:    m.Modified,
+		GameBranch:  m.GameBranch,
+		GameVersion: m.GameVersion,
+		Revision:    m.Revision,
+	}
 }
 
 func (f *ficsitCLI) MakeCurrentExportedProfile() (*ExportedProfile, error) {
@@ -219,13 +456,29 @@ func (f *ficsitCLI) MakeCurrentExportedProfile() (*ExportedProfile, error) {
 		return nil, fmt.Errorf("failed to get lockfile: %w", err)
 	}
 
+	profileMetadata := f.GetProfileMetadata(*profileName)
+
+	// The profile may have been made for another branch or version than the install it is exported from
+	gameVersion := profileMetadata.GameVersion
+	gameBranch := profileMetadata.GameBranch
 	installMetadata, ok := f.installationMetadata.Load(selectedInstallation.Path)
-	var gameVersion int
 	if ok && installMetadata.Info != nil {
-		gameVersion = installMetadata.Info.Version
+		if gameVersion == 0 {
+			gameVersion = installMetadata.Info.Version
+		}
+		if gameBranch == "" {
+			gameBranch = installMetadata.Info.Branch
+		}
 	}
 	metadata := &ExportedProfileMetadata{
 		GameVersion: gameVersion,
+		GameBranch:  gameBranch,
+		Description: profileMetadata.Description,
+		Tags:        profileMetadata.Tags,
+		Author:      profileMetadata.Author,
+		Created:     profileMetadata.Created,
+		Modified:    profileMetadata.Modified,
+		Revision:    profileMetadata.Revision,
 	}
 
 	if lockfile == nil {
@@ -281,89 +534,137 @@ func (f *ficsitCLI) ExportCurrentProfile() error {
 	return nil
 }
 
-func (f *ficsitCLI) ReadExportedProfileMetadata(file string) (*ExportedProfileMetadata, error) {
-	l := slog.With(slog.String("task", "readExportedProfileMetadata"), slog.String("file", file))
-
+// readExportedProfile reads an SMM3 profile export, or converts an SMM2 one.
+// The returned warnings describe the parts of an SMM2 profile that could not be converted
+func readExportedProfile(file string) (*ExportedProfile, []string, error) {
 	fileBytes, err := os.ReadFile(file)
 	if err != nil {
-		l.Error("failed to read exported profile", slog.Any("error", err))
-		return nil, fmt.Errorf("failed to read exported profile: %w", err)
+		return nil, nil, fmt.Errorf("failed to read exported profile: %w", err)
 	}
 
+	return parseExportedProfile(strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)), fileBytes)
+}
+
+// parseExportedProfile parses the contents of an exported profile. The name is only used for SMM2 profiles,
+// which do not store it
+func parseExportedProfile(name string, data []byte) (*ExportedProfile, []string, error) {
 	var exportedProfile ExportedProfile
-	err = json.Unmarshal(fileBytes, &exportedProfile)
+	err := json.Unmarshal(data, &exportedProfile)
 	if err != nil {
-		_, err = zip.NewReader(bytes.NewReader(fileBytes), int64(len(fileBytes)))
-		if err == nil {
-			// SMM2 profile is a zip file, can't import
-			return nil, fmt.Errorf("profiles exported from SMM2 cannot be loaded in SMM3")
+		if isZip(data) {
+			// SMM2 profile is a zip file
+			smm2Profile, warnings, convertErr := convertSMM2ProfileZip(name, data)
+			if convertErr != nil {
+				return nil, nil, fmt.Errorf("failed to convert SMM2 profile: %w", convertErr)
+			}
+			return smm2Profile, warnings, nil
 		}
 
-		l.Error("failed to unmarshal exported profile", slog.Any("error", err))
-		return nil, fmt.Errorf("failed to parse exported profile: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse exported profile: %w", err)
 	}
 
-	return exportedProfile.Metadata, nil
+	return &exportedProfile, nil, nil
 }
 
-func (f *ficsitCLI) ImportProfile(name string, file string) error {
-	return f.action(ActionImportProfile, newSimpleItem(name), func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
-		l = l.With(slog.String("file", file))
+func (f *ficsitCLI) ReadExportedProfileMetadata(file string) (*ExportedProfileMetadata, error) {
+	l := slog.With(slog.String("task", "readExportedProfileMetadata"), slog.String("file", file))
 
-		selectedInstallation := f.GetSelectedInstall()
+	exportedProfile, _, err := readExportedProfile(file)
+	if err != nil {
+		l.Error("failed to read exported profile", slog.Any("error", err))
+		return nil, err
+	}
 
-		if selectedInstallation == nil {
-			l.Error("no installation selected")
-			return fmt.Errorf("no installation selected")
-		}
+	return exportedProfile.Metadata, nil
+}
+
+func (f *ficsitCLI) ImportProfile(name string, file string, mode ProfileImportMode) error {
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
-		profileData, err := os.ReadFile(file)
+	scope := installProfileScope(selectedInstallation).with(profileScope(name))
+	return f.action(ActionImportProfile, newSimpleItem(name), scope, func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		l = l.With(slog.String("file", file), slog.String("mode", string(mode)))
+
+		exportedProfile, warnings, err := readExportedProfile(file)
 		if err != nil {
 			l.Error("failed to read exported profile", slog.Any("error", err))
 			return fmt.Errorf("failed to read profile file: %w", err)
 		}
-
-		var exportedProfile ExportedProfile
-		err = json.Unmarshal(profileData, &exportedProfile)
-		if err != nil {
-			l.Error("failed to unmarshal exported profile", slog.Any("error", err))
-			return fmt.Errorf("failed to read profile file: %w", err)
+		for _, warning := range warnings {
+			l.Warn("profile conversion warning", slog.String("warning", warning))
 		}
 
-		profile, err := f.ficsitCli.Profiles.AddProfile(name)
-		if err != nil {
-			l.Error("failed to add profile", slog.Any("error", err))
-			return fmt.Errorf("failed to add imported profile: %w", err)
+		lockfile := &exportedProfile.LockFile
+		switch mode {
+		case ProfileImportModeAsIs, ProfileImportModeFrozen:
+		case ProfileImportModeResolveLatest:
+			// The lockfile is only used as a hint by the resolver, so without it the latest compatible versions are picked
+			lockfile = resolver.NewLockfile()
+		default:
+			return fmt.Errorf("unknown import mode: %s", mode)
 		}
 
-		profile.Mods = exportedProfile.Profile.Mods
-
 		currentProfile := selectedInstallation.Profile
 
-		_ = selectedInstallation.SetProfile(f.ficsitCli, name)
+		err = f.updateState(func() error {
+			profile, err := f.ficsitCli.Profiles.AddProfile(name)
+			if err != nil {
+				l.Error("failed to add profile", slog.Any("error", err))
+				return fmt.Errorf("failed to add imported profile: %w", err)
+			}
+
+			profile.Mods = exportedProfile.Profile.Mods
 
-		err = selectedInstallation.WriteLockFile(f.ficsitCli, &exportedProfile.LockFile)
+			_ = selectedInstallation.SetProfile(f.ficsitCli, name)
+			return nil
+		})
 		if err != nil {
-			_ = selectedInstallation.SetProfile(f.ficsitCli, currentProfile)
-			_ = f.ficsitCli.Profiles.DeleteProfile(name)
+			return err
+		}
+
+		err = selectedInstallation.WriteLockFile(f.ficsitCli, lockfile)
+		if err != nil {
+			_ = f.updateState(func() error {
+				_ = selectedInstallation.SetProfile(f.ficsitCli, currentProfile)
+				_ = f.ficsitCli.Profiles.DeleteProfile(name)
+				return nil
+			})
+			f.deleteProfileMetadata(name)
 			l.Error("failed to write lockfile", slog.Any("error", err))
 			return fmt.Errorf("failed to write profile: %w", err)
 		}
 
+		f.initProfileMetadata(name, exportedProfile.Metadata.profileMetadata())
+
 		f.EmitGlobals()
 
-		installErr := f.apply(l, taskChannel)
+		var installErr error
+		if mode == ProfileImportModeFrozen {
+			installErr = f.applyFrozen(l, selectedInstallation, lockfile, taskChannel)
+		} else {
+			installErr = f.apply(l, selectedInstallation, taskChannel)
+		}
 
 		if installErr != nil {
-			_ = f.ficsitCli.Profiles.DeleteProfile(name)
+			_ = f.updateState(func() error {
+				_ = f.ficsitCli.Profiles.DeleteProfile(name)
+				return nil
+			})
+			f.deleteProfileMetadata(name)
 			l.Error("failed to validate installation", slog.Any("error", installErr))
 			return installErr
 		}
 
-		err = f.ficsitCli.Profiles.Save()
-		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
-		}
+		_ = f.updateState(func() error {
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
 
 		return nil
 	})
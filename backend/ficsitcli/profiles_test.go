@@ -0,0 +1,147 @@
+package ficsitcli
+
+import (
+	"testing"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+)
+
+func TestMergeProfileMods(t *testing.T) {
+	tests := []struct {
+		name     string
+		into     map[string]cli.ProfileMod
+		from     map[string]cli.ProfileMod
+		strategy MergeStrategy
+		want     map[string]cli.ProfileMod
+		wantErr  bool
+	}{
+		{
+			name:     "adds missing mods",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"ContentLib": {Version: ">=1.0.0", Enabled: false}},
+			strategy: MergeStrategyFail,
+			want: map[string]cli.ProfileMod{
+				"SML":        {Version: ">=3.7.0", Enabled: true},
+				"ContentLib": {Version: ">=1.0.0", Enabled: false},
+			},
+		},
+		{
+			name:     "into a profile without mods",
+			into:     nil,
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			strategy: MergeStrategyKeep,
+			want:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+		},
+		{
+			name:     "same constraint enabled in either",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: false}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			strategy: MergeStrategyFail,
+			want:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+		},
+		{
+			name:     "keep",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.8.0", Enabled: false}},
+			strategy: MergeStrategyKeep,
+			want:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+		},
+		{
+			name:     "replace",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: false}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.8.0", Enabled: false}},
+			strategy: MergeStrategyReplace,
+			want:     map[string]cli.ProfileMod{"SML": {Version: ">=3.8.0", Enabled: false}},
+		},
+		{
+			name:     "intersect",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: "<4.0.0", Enabled: true}},
+			strategy: MergeStrategyIntersect,
+			want:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0 <4.0.0", Enabled: true}},
+		},
+		{
+			name:     "intersect empty",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=4.0.0", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: "<3.0.0", Enabled: true}},
+			strategy: MergeStrategyIntersect,
+			wantErr:  true,
+		},
+		{
+			name:     "intersect invalid constraint",
+			into:     map[string]cli.ProfileMod{"SML": {Version: "not-a-constraint", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.0.0", Enabled: true}},
+			strategy: MergeStrategyIntersect,
+			wantErr:  true,
+		},
+		{
+			name:     "fail on differing constraints",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.8.0", Enabled: true}},
+			strategy: MergeStrategyFail,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown strategy",
+			into:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			from:     map[string]cli.ProfileMod{"SML": {Version: ">=3.8.0", Enabled: true}},
+			strategy: "other",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeProfileMods(tt.into, tt.from, tt.strategy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mergeProfileMods() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("mergeProfileMods() = %v, want %v", got, tt.want)
+			}
+			for modReference, wantMod := range tt.want {
+				gotMod, ok := got[modReference]
+				if !ok {
+					t.Fatalf("mergeProfileMods() is missing %s", modReference)
+				}
+				if gotMod.Enabled != wantMod.Enabled {
+					t.Errorf("%s enabled = %v, want %v", modReference, gotMod.Enabled, wantMod.Enabled)
+				}
+				if !constraintsEqual(t, gotMod.Version, wantMod.Version) {
+					t.Errorf("%s version = %s, want %s", modReference, gotMod.Version, wantMod.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeProfileModsDoesNotModifyInto(t *testing.T) {
+	into := map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}}
+	from := map[string]cli.ProfileMod{"SML": {Version: ">=3.8.0", Enabled: true}, "ContentLib": {Version: ">=1.0.0", Enabled: true}}
+
+	_, err := mergeProfileMods(into, from, MergeStrategyReplace)
+	if err != nil {
+		t.Fatalf("mergeProfileMods() error = %v", err)
+	}
+	if len(into) != 1 || into["SML"].Version != ">=3.7.0" {
+		t.Errorf("mergeProfileMods() modified into: %v", into)
+	}
+}
+
+// constraintsEqual compares constraints by the versions they allow, since intersections are not kept as written
+func constraintsEqual(t *testing.T, a string, b string) bool {
+	t.Helper()
+	aConstraint, err := semver.NewConstraint(a)
+	if err != nil {
+		t.Fatalf("invalid constraint %s: %v", a, err)
+	}
+	bConstraint, err := semver.NewConstraint(b)
+	if err != nil {
+		t.Fatalf("invalid constraint %s: %v", b, err)
+	}
+	return aConstraint.Equal(bConstraint)
+}
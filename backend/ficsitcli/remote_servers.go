@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
 )
@@ -29,14 +31,22 @@ func (f *ficsitCLI) AddRemoteServer(path string, name string) error {
 	}
 	l := slog.With(slog.String("task", "addRemoteServer"), slog.String("path", path))
 
-	installation, err := f.ficsitCli.Installations.AddInstallation(f.ficsitCli, path, f.GetFallbackProfile())
-	if err != nil {
-		return fmt.Errorf("failed to add installation: %w", err)
-	}
+	var installation *cli.Installation
+	err := f.updateState(func() error {
+		var err error
+		installation, err = f.ficsitCli.Installations.AddInstallation(f.ficsitCli, path, f.GetFallbackProfile())
+		if err != nil {
+			return fmt.Errorf("failed to add installation: %w", err)
+		}
 
-	err = f.ficsitCli.Installations.Save()
+		err = f.ficsitCli.Installations.Save()
+		if err != nil {
+			l.Error("failed to save installations", slog.Any("error", err))
+		}
+		return nil
+	})
 	if err != nil {
-		l.Error("failed to save installations", slog.Any("error", err))
+		return err
 	}
 
 	if name != "" {
@@ -74,13 +84,26 @@ func (f *ficsitCLI) RemoveRemoteServer(path string) error {
 	if metadata.Info != nil && metadata.Info.Location != common.LocationTypeRemote {
 		return fmt.Errorf("installation is not remote")
 	}
-	err := f.ficsitCli.Installations.DeleteInstallation(path)
+	// The server cannot be removed while an action is running on it
+	_, unlock, err := f.lockScope(installScope(path))
 	if err != nil {
-		return fmt.Errorf("failed to delete installation: %w", err)
+		return err
 	}
-	err = f.ficsitCli.Installations.Save()
+	defer unlock()
+
+	err = f.updateState(func() error {
+		err := f.ficsitCli.Installations.DeleteInstallation(path)
+		if err != nil {
+			return fmt.Errorf("failed to delete installation: %w", err)
+		}
+		err = f.ficsitCli.Installations.Save()
+		if err != nil {
+			slog.Error("failed to save installations", slog.Any("error", err))
+		}
+		return nil
+	})
 	if err != nil {
-		slog.Error("failed to save installations", slog.Any("error", err))
+		return err
 	}
 	f.installationMetadata.Delete(path)
 
@@ -0,0 +1,315 @@
+package ficsitcli
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+// SMM2 profiles, both exported zips and the profile folders, contain a manifest of the mods the user selected,
+// a lockfile of the resolved versions, and optionally some metadata
+
+const (
+	smm2ManifestFile = "manifest.json"
+	smm2MetadataFile = "metadata.json"
+)
+
+// SMM2 used different lockfile names across versions
+var smm2LockfileFiles = []string{"lockfile.json", "lock.json"}
+
+// SMM2 items that have no equivalent in ficsit-cli
+var smm2UnsupportedItems = map[string]string{
+	"bootstrapper": "the bootstrapper is no longer used",
+}
+
+type smm2Manifest struct {
+	Items []smm2ManifestItem `json:"items"`
+}
+
+type smm2ManifestItem struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Enabled *bool  `json:"enabled"`
+}
+
+type smm2LockfileItem struct {
+	Version      string            `json:"version"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+type smm2Metadata struct {
+	GameVersion json.RawMessage `json:"gameVersion"`
+}
+
+func isZip(data []byte) bool {
+	_, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	return err == nil
+}
+
+// convertSMM2ProfileZip converts an SMM2 profile export to the SMM3 format.
+// The returned warnings describe the entries that could not be converted
+func convertSMM2ProfileZip(name string, data []byte) (*ExportedProfile, []string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open SMM2 profile: %w", err)
+	}
+	return convertSMM2Profile(name, reader)
+}
+
+// convertSMM2Profile converts an SMM2 profile, read from either an export zip or a profile folder
+func convertSMM2Profile(name string, profileFS fs.FS) (*ExportedProfile, []string, error) {
+	var warnings []string
+
+	manifestData, err := fs.ReadFile(profileFS, smm2ManifestFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read SMM2 profile manifest: %w", err)
+	}
+	var manifest smm2Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse SMM2 profile manifest: %w", err)
+	}
+
+	exportedProfile := &ExportedProfile{
+		Profile: cli.Profile{
+			Name: name,
+			Mods: make(map[string]cli.ProfileMod),
+		},
+		LockFile: *resolver.NewLockfile(),
+	}
+
+	for _, item := range manifest.Items {
+		if reason, ok := smm2UnsupportedItems[item.ID]; ok {
+			warnings = append(warnings, fmt.Sprintf("skipped %s: %s", item.ID, reason))
+			continue
+		}
+		if item.ID == "" {
+			warnings = append(warnings, "skipped manifest entry without a mod reference")
+			continue
+		}
+		version := item.Version
+		if version == "" {
+			version = ">=0.0.0"
+		} else if _, err := semver.NewConstraint(version); err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid version constraint %s for %s, using latest", version, item.ID))
+			version = ">=0.0.0"
+		}
+		enabled := true
+		if item.Enabled != nil {
+			enabled = *item.Enabled
+		}
+		exportedProfile.Profile.Mods[item.ID] = cli.ProfileMod{
+			Version: version,
+			Enabled: enabled,
+		}
+	}
+
+	lockfileData, err := readFirstFile(profileFS, smm2LockfileFiles)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("failed to read SMM2 profile lockfile: %w", err)
+		}
+		warnings = append(warnings, "profile has no lockfile, the latest compatible versions will be installed")
+	} else {
+		var lockfile map[string]smm2LockfileItem
+		if err := json.Unmarshal(lockfileData, &lockfile); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SMM2 profile lockfile: %w", err)
+		}
+		for modReference, item := range lockfile {
+			if reason, ok := smm2UnsupportedItems[modReference]; ok {
+				warnings = append(warnings, fmt.Sprintf("skipped locked %s: %s", modReference, reason))
+				continue
+			}
+			if _, err := semver.NewVersion(item.Version); err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped locked %s: invalid version %s", modReference, item.Version))
+				continue
+			}
+			dependencies := make(map[string]string, len(item.Dependencies))
+			for dependency, constraint := range item.Dependencies {
+				if _, ok := smm2UnsupportedItems[dependency]; ok {
+					continue
+				}
+				dependencies[dependency] = constraint
+			}
+			// SMM2 lockfiles do not contain download information, that will be filled in when resolving
+			exportedProfile.LockFile.Mods[modReference] = resolver.LockedMod{
+				Version:      item.Version,
+				Dependencies: dependencies,
+				Targets:      make(map[string]resolver.LockedModTarget),
+			}
+		}
+	}
+
+	metadataData, err := fs.ReadFile(profileFS, smm2MetadataFile)
+	if err == nil {
+		var metadata smm2Metadata
+		if err := json.Unmarshal(metadataData, &metadata); err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to parse metadata: %s", err.Error()))
+		} else if gameVersion, ok := parseSMM2GameVersion(metadata.GameVersion); ok {
+			exportedProfile.Metadata = &ExportedProfileMetadata{
+				GameVersion: gameVersion,
+			}
+		}
+	}
+
+	sort.Strings(warnings)
+
+	return exportedProfile, warnings, nil
+}
+
+func readFirstFile(fsys fs.FS, names []string) ([]byte, error) {
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// parseSMM2GameVersion handles the game version being stored both as a number and as a string
+func parseSMM2GameVersion(raw json.RawMessage) (int, bool) {
+	if len(raw) == 0 {
+		return 0, false
+	}
+	var number int
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number, true
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		number, err := strconv.Atoi(str)
+		if err == nil {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+type SMM2ProfileMigrationStatus string
+
+const (
+	SMM2ProfileMigrationStatusMigrated      SMM2ProfileMigrationStatus = "migrated"
+	SMM2ProfileMigrationStatusAlreadyExists SMM2ProfileMigrationStatus = "alreadyExists"
+	SMM2ProfileMigrationStatusFailed        SMM2ProfileMigrationStatus = "failed"
+)
+
+type SMM2ProfileMigrationResult struct {
+	Profile string                     `json:"profile"`
+	Status  SMM2ProfileMigrationStatus `json:"status"`
+	// Installs that had this profile selected in SMM2, and now use it again
+	Installs []string `json:"installs"`
+	Warnings []string `json:"warnings"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// MigrateSMM2Profile converts an SMM2 profile folder into a profile,
+// and selects it on the installs that were using it in SMM2.
+// Profiles that already exist, such as the ones ficsit-cli imported on first start, are kept,
+// but are still selected on those installs
+func (f *ficsitCLI) MigrateSMM2Profile(name string, dir string) SMM2ProfileMigrationResult {
+	l := slog.With(slog.String("task", "migrateSMM2Profile"), slog.String("profile", name), slog.String("dir", dir))
+
+	result := SMM2ProfileMigrationResult{
+		Profile:  name,
+		Installs: []string{},
+	}
+
+	exportedProfile, warnings, err := convertSMM2Profile(name, os.DirFS(dir))
+	if err != nil {
+		l.Error("failed to convert profile", slog.Any("error", err))
+		result.Status = SMM2ProfileMigrationStatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Warnings = warnings
+
+	if f.GetProfile(name) != nil {
+		result.Status = SMM2ProfileMigrationStatusAlreadyExists
+	} else {
+		err := f.updateState(func() error {
+			profile, err := f.ficsitCli.Profiles.AddProfile(name)
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+			profile.Mods = exportedProfile.Profile.Mods
+
+			err = f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
+			}
+			return nil
+		})
+		if err != nil {
+			l.Error("failed to add profile", slog.Any("error", err))
+			result.Status = SMM2ProfileMigrationStatusFailed
+			result.Error = err.Error()
+			return result
+		}
+
+		f.initProfileMetadata(name, exportedProfile.Metadata.profileMetadata())
+
+		result.Status = SMM2ProfileMigrationStatusMigrated
+	}
+
+	for _, install := range f.ficsitCli.Installations.Installations {
+		if settings.SMM2SelectedProfile[install.Path] != name {
+			continue
+		}
+
+		err := f.updateState(func() error {
+			return install.SetProfile(f.ficsitCli, name) //nolint:wrapcheck
+		})
+		if err != nil {
+			l.Error("failed to select profile", slog.String("install", install.Path), slog.Any("error", err))
+			continue
+		}
+		result.Installs = append(result.Installs, install.Path)
+
+		if len(exportedProfile.LockFile.Mods) == 0 {
+			continue
+		}
+		// Keep the versions SMM2 had installed, unless the install already has a lockfile for this profile
+		existingLockfile, err := install.LockFile(f.ficsitCli)
+		if err != nil {
+			l.Warn("failed to read lockfile", slog.String("install", install.Path), slog.Any("error", err))
+			continue
+		}
+		if existingLockfile == nil {
+			err = install.WriteLockFile(f.ficsitCli, &exportedProfile.LockFile)
+			if err != nil {
+				l.Warn("failed to write lockfile", slog.String("install", install.Path), slog.Any("error", err))
+			}
+		}
+	}
+	sort.Strings(result.Installs)
+
+	if len(result.Installs) > 0 {
+		_ = f.updateState(func() error {
+			err := f.ficsitCli.Installations.Save()
+			if err != nil {
+				l.Error("failed to save installations", slog.Any("error", err))
+			}
+			return nil
+		})
+	}
+
+	f.EmitGlobals()
+
+	return result
+}
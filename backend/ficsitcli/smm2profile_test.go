@@ -0,0 +1,166 @@
+package ficsitcli
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+)
+
+func TestConvertSMM2Profile(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           map[string]string
+		wantMods        map[string]cli.ProfileMod
+		wantLocked      map[string]string
+		wantGameVersion int
+		wantWarnings    []string
+		wantErr         bool
+	}{
+		{
+			name: "manifest and lockfile",
+			files: map[string]string{
+				"manifest.json": `{"items": [{"id": "SML", "version": ">=3.7.0"}, {"id": "ContentLib", "enabled": false}]}`,
+				"lockfile.json": `{"SML": {"version": "3.7.0"}, "ContentLib": {"version": "1.2.0", "dependencies": {"SML": "^3.7.0", "bootstrapper": "^2.0.0"}}}`,
+			},
+			wantMods: map[string]cli.ProfileMod{
+				"SML":        {Version: ">=3.7.0", Enabled: true},
+				"ContentLib": {Version: ">=0.0.0", Enabled: false},
+			},
+			wantLocked: map[string]string{"SML": "3.7.0", "ContentLib": "1.2.0"},
+		},
+		{
+			name: "older lockfile name",
+			files: map[string]string{
+				"manifest.json": `{"items": [{"id": "SML", "version": ">=3.7.0"}]}`,
+				"lock.json":     `{"SML": {"version": "3.7.0"}}`,
+			},
+			wantMods:   map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			wantLocked: map[string]string{"SML": "3.7.0"},
+		},
+		{
+			name: "unsupported and invalid entries",
+			files: map[string]string{
+				"manifest.json": `{"items": [{"id": "bootstrapper", "version": ">=2.0.0"}, {"id": ""}, {"id": "SML", "version": "latest"}]}`,
+				"lockfile.json": `{"bootstrapper": {"version": "2.0.0"}, "SML": {"version": "not-a-version"}}`,
+			},
+			wantMods:   map[string]cli.ProfileMod{"SML": {Version: ">=0.0.0", Enabled: true}},
+			wantLocked: map[string]string{},
+			wantWarnings: []string{
+				"invalid version constraint latest for SML, using latest",
+				"skipped bootstrapper: the bootstrapper is no longer used",
+				"skipped locked SML: invalid version not-a-version",
+				"skipped locked bootstrapper: the bootstrapper is no longer used",
+				"skipped manifest entry without a mod reference",
+			},
+		},
+		{
+			name: "without lockfile",
+			files: map[string]string{
+				"manifest.json": `{"items": [{"id": "SML", "version": ">=3.7.0"}]}`,
+			},
+			wantMods:     map[string]cli.ProfileMod{"SML": {Version: ">=3.7.0", Enabled: true}},
+			wantLocked:   map[string]string{},
+			wantWarnings: []string{"profile has no lockfile, the latest compatible versions will be installed"},
+		},
+		{
+			name: "numeric game version",
+			files: map[string]string{
+				"manifest.json": `{"items": []}`,
+				"lockfile.json": `{}`,
+				"metadata.json": `{"gameVersion": 211839}`,
+			},
+			wantMods:        map[string]cli.ProfileMod{},
+			wantLocked:      map[string]string{},
+			wantGameVersion: 211839,
+		},
+		{
+			name: "string game version",
+			files: map[string]string{
+				"manifest.json": `{"items": []}`,
+				"lockfile.json": `{}`,
+				"metadata.json": `{"gameVersion": "211839"}`,
+			},
+			wantMods:        map[string]cli.ProfileMod{},
+			wantLocked:      map[string]string{},
+			wantGameVersion: 211839,
+		},
+		{
+			name: "invalid metadata",
+			files: map[string]string{
+				"manifest.json": `{"items": []}`,
+				"lockfile.json": `{}`,
+				"metadata.json": `{"gameVersion": "unknown"}`,
+			},
+			wantMods:   map[string]cli.ProfileMod{},
+			wantLocked: map[string]string{},
+		},
+		{
+			name:    "without manifest",
+			files:   map[string]string{"lockfile.json": `{}`},
+			wantErr: true,
+		},
+		{
+			name: "invalid lockfile",
+			files: map[string]string{
+				"manifest.json": `{"items": []}`,
+				"lockfile.json": `[`,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profileFS := fstest.MapFS{}
+			for name, content := range tt.files {
+				profileFS[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+
+			got, warnings, err := convertSMM2Profile("Imported", profileFS)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertSMM2Profile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Profile.Name != "Imported" {
+				t.Errorf("profile name = %s, want Imported", got.Profile.Name)
+			}
+			if len(got.Profile.Mods) != len(tt.wantMods) {
+				t.Errorf("mods = %v, want %v", got.Profile.Mods, tt.wantMods)
+			}
+			for modReference, wantMod := range tt.wantMods {
+				if gotMod := got.Profile.Mods[modReference]; gotMod != wantMod {
+					t.Errorf("mod %s = %v, want %v", modReference, gotMod, wantMod)
+				}
+			}
+
+			if len(got.LockFile.Mods) != len(tt.wantLocked) {
+				t.Errorf("locked mods = %v, want %v", got.LockFile.Mods, tt.wantLocked)
+			}
+			for modReference, wantVersion := range tt.wantLocked {
+				if gotVersion := got.LockFile.Mods[modReference].Version; gotVersion != wantVersion {
+					t.Errorf("locked %s = %s, want %s", modReference, gotVersion, wantVersion)
+				}
+				if _, ok := got.LockFile.Mods[modReference].Dependencies["bootstrapper"]; ok {
+					t.Errorf("locked %s depends on the bootstrapper", modReference)
+				}
+			}
+
+			gameVersion := 0
+			if got.Metadata != nil {
+				gameVersion = got.Metadata.GameVersion
+			}
+			if gameVersion != tt.wantGameVersion {
+				t.Errorf("game version = %d, want %d", gameVersion, tt.wantGameVersion)
+			}
+
+			if !slices.Equal(warnings, tt.wantWarnings) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
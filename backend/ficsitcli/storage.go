@@ -0,0 +1,48 @@
+package ficsitcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
+)
+
+// SMM-specific data is stored next to the ficsit-cli profiles and installations,
+// with the file name configured through the given viper key
+
+func smmDataPath(fileKey string) string {
+	return filepath.Join(viper.GetString("local-dir"), viper.GetString(fileKey))
+}
+
+// readSMMData unmarshals the data file into v. Returns false if the file does not exist
+func readSMMData(fileKey string, v any) (bool, error) {
+	data, err := os.ReadFile(smmDataPath(fileKey))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read %s: %w", viper.GetString(fileKey), err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to unmarshal %s: %w", viper.GetString(fileKey), err)
+	}
+
+	return true, nil
+}
+
+func writeSMMData(fileKey string, v any) error {
+	data, err := utils.JSONMarshal(v, 2)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", viper.GetString(fileKey), err)
+	}
+	err = os.WriteFile(smmDataPath(fileKey), data, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", viper.GetString(fileKey), err)
+	}
+	return nil
+}
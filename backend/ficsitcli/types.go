@@ -17,27 +17,37 @@ const (
 type installationMetadata struct {
 	State InstallState         `json:"state"`
 	Info  *common.Installation `json:"info"`
+	// IncompatibleMods are the mods in the lockfile that do not support the current game version,
+	// found when the game is updated while SMM is running
+	IncompatibleMods []string `json:"incompatibleMods,omitempty"`
 }
 
 type Action string
 
 const (
-	ActionInstall       Action = "install"
-	ActionUninstall     Action = "uninstall"
-	ActionEnable        Action = "enable"
-	ActionDisable       Action = "disable"
-	ActionSelectInstall Action = "selectInstall"
-	ActionToggleMods    Action = "toggleMods"
-	ActionSelectProfile Action = "selectProfile"
-	ActionImportProfile Action = "importProfile"
-	ActionUpdate        Action = "update"
-	ActionApply         Action = "apply"
+	ActionInstall            Action = "install"
+	ActionUninstall          Action = "uninstall"
+	ActionEnable             Action = "enable"
+	ActionDisable            Action = "disable"
+	ActionSelectInstall      Action = "selectInstall"
+	ActionToggleMods         Action = "toggleMods"
+	ActionSelectProfile      Action = "selectProfile"
+	ActionImportProfile      Action = "importProfile"
+	ActionUpdate             Action = "update"
+	ActionApply              Action = "apply"
+	ActionUpdateSubscription Action = "updateSubscription"
+	ActionApplyFrozen        Action = "applyFrozen"
+	ActionUpdateAll          Action = "updateAll"
+	ActionAutoUpdate         Action = "autoUpdate"
+	ActionUndo               Action = "undo"
 )
 
 type Progress struct {
-	Action Action                    `json:"action"`
-	Item   ProgressItem              `json:"item"`
-	Tasks  map[string]utils.Progress `json:"tasks"`
+	Action Action       `json:"action"`
+	Item   ProgressItem `json:"item"`
+	// Installs are the installs the action is running on
+	Installs []string                  `json:"installs"`
+	Tasks    map[string]utils.Progress `json:"tasks"`
 }
 
 type ProgressItem struct {
@@ -60,14 +70,39 @@ func newItem(name, version string) ProgressItem {
 	}
 }
 
-func newProgress(action Action, item ProgressItem) *Progress {
+func newProgress(action Action, item ProgressItem, installs []string) *Progress {
 	return &Progress{
-		Action: action,
-		Item:   item,
-		Tasks:  make(map[string]utils.Progress),
+		Action:   action,
+		Item:     item,
+		Installs: installs,
+		Tasks:    make(map[string]utils.Progress),
 	}
 }
 
+type MergeStrategy string
+
+const (
+	// MergeStrategyKeep keeps the constraint of the profile being merged into
+	MergeStrategyKeep MergeStrategy = "keep"
+	// MergeStrategyReplace uses the constraint of the profile being merged from
+	MergeStrategyReplace MergeStrategy = "replace"
+	// MergeStrategyIntersect uses the intersection of both constraints, failing if it is empty
+	MergeStrategyIntersect MergeStrategy = "intersect"
+	// MergeStrategyFail fails the merge if any constraints differ
+	MergeStrategyFail MergeStrategy = "fail"
+)
+
+type ProfileImportMode string
+
+const (
+	// ProfileImportModeAsIs installs the versions in the imported lockfile
+	ProfileImportModeAsIs ProfileImportMode = "asIs"
+	// ProfileImportModeResolveLatest ignores the imported lockfile and resolves the latest versions compatible with the install
+	ProfileImportModeResolveLatest ProfileImportMode = "resolveLatest"
+	// ProfileImportModeFrozen installs exactly the versions in the imported lockfile, failing if any cannot be installed
+	ProfileImportModeFrozen ProfileImportMode = "frozen"
+)
+
 var AllInstallationStates = []struct {
 	Value  InstallState
 	TSName string
@@ -92,4 +127,49 @@ var AllActionTypes = []struct {
 	{ActionImportProfile, "IMPORT_PROFILE"},
 	{ActionUpdate, "UPDATE"},
 	{ActionApply, "APPLY"},
+	{ActionUpdateSubscription, "UPDATE_SUBSCRIPTION"},
+	{ActionApplyFrozen, "APPLY_FROZEN"},
+	{ActionUpdateAll, "UPDATE_ALL"},
+	{ActionAutoUpdate, "AUTO_UPDATE"},
+	{ActionUndo, "UNDO"},
+}
+
+var AllMergeStrategies = []struct {
+	Value  MergeStrategy
+	TSName string
+}{
+	{MergeStrategyKeep, "KEEP"},
+	{MergeStrategyReplace, "REPLACE"},
+	{MergeStrategyIntersect, "INTERSECT"},
+	{MergeStrategyFail, "FAIL"},
+}
+
+var AllProfileImportModes = []struct {
+	Value  ProfileImportMode
+	TSName string
+}{
+	{ProfileImportModeAsIs, "AS_IS"},
+	{ProfileImportModeResolveLatest, "RESOLVE_LATEST"},
+	{ProfileImportModeFrozen, "FROZEN"},
+}
+
+var AllSMM2ProfileMigrationStatuses = []struct {
+	Value  SMM2ProfileMigrationStatus
+	TSName string
+}{
+	{SMM2ProfileMigrationStatusMigrated, "MIGRATED"},
+	{SMM2ProfileMigrationStatusAlreadyExists, "ALREADY_EXISTS"},
+	{SMM2ProfileMigrationStatusFailed, "FAILED"},
+}
+
+var AllProfileSubscriptionChangeTypes = []struct {
+	Value  ProfileSubscriptionChangeType
+	TSName string
+}{
+	{ProfileSubscriptionChangeAdded, "ADDED"},
+	{ProfileSubscriptionChangeRemoved, "REMOVED"},
+	{ProfileSubscriptionChangeEnabled, "ENABLED"},
+	{ProfileSubscriptionChangeDisabled, "DISABLED"},
+	{ProfileSubscriptionChangeConstraintChanged, "CONSTRAINT_CHANGED"},
+	{ProfileSubscriptionChangeVersionChanged, "VERSION_CHANGED"},
 }
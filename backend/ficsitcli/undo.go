@@ -0,0 +1,417 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	resolver "github.com/satisfactorymodding/ficsit-resolver"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
+)
+
+const undoHistorySize = 10
+
+// undoableActions are the actions that change profiles, the profile of installs, or lockfiles.
+// Only these are snapshotted, since snapshotting reads the lockfile of every install in scope, which is slow for remote installs.
+// Applying only brings the installs in line with their profile, so there is nothing to undo
+var undoableActions = []Action{
+	ActionInstall,
+	ActionUninstall,
+	ActionEnable,
+	ActionDisable,
+	ActionToggleMods,
+	ActionSelectProfile,
+	ActionImportProfile,
+	ActionUpdate,
+	ActionUpdateSubscription,
+	ActionApplyFrozen,
+	ActionUpdateAll,
+	ActionAutoUpdate,
+}
+
+// UndoEntry is an action that can be undone, shown in the undo history
+type UndoEntry struct {
+	ID     string       `json:"id"`
+	Action Action       `json:"action"`
+	Item   ProgressItem `json:"item"`
+	Time   time.Time    `json:"time"`
+	// Failed is set when the action failed after changing some of the state, which undoing restores
+	Failed bool `json:"failed,omitempty"`
+}
+
+// undoSnapshot is the state of an action's scope from before the action
+type undoSnapshot struct {
+	UndoEntry
+	profiles map[string]map[string]cli.ProfileMod
+	installs map[string]installSnapshot
+	// missingProfiles did not exist before the action, undoing deletes the ones it created
+	missingProfiles []string
+}
+
+type installSnapshot struct {
+	profile  string
+	vanilla  bool
+	lockfile *resolver.LockFile
+}
+
+func installHistoryKey(installPath string) string {
+	return "install:" + installPath
+}
+
+func profileHistoryKey(profile string) string {
+	return "profile:" + profile
+}
+
+func (s *undoSnapshot) historyKeys() []string {
+	var keys []string
+	for installPath := range s.installs {
+		keys = append(keys, installHistoryKey(installPath))
+	}
+	if len(keys) > 0 {
+		return keys
+	}
+	for profileName := range s.profiles {
+		keys = append(keys, profileHistoryKey(profileName))
+	}
+	for _, profileName := range s.missingProfiles {
+		keys = append(keys, profileHistoryKey(profileName))
+	}
+	return keys
+}
+
+func (f *ficsitCLI) snapshotScope(action Action, item ProgressItem, scope actionScope) *undoSnapshot {
+	if !slices.Contains(undoableActions, action) {
+		return nil
+	}
+
+	snapshot := &undoSnapshot{
+		UndoEntry: UndoEntry{
+			ID:     uuid.NewString(),
+			Action: action,
+			Item:   item,
+		},
+		profiles: make(map[string]map[string]cli.ProfileMod),
+		installs: make(map[string]installSnapshot),
+	}
+
+	var installations []*cli.Installation
+	f.stateMutex.Lock()
+	for _, profileName := range scope.profiles {
+		if profile := f.ficsitCli.Profiles.GetProfile(profileName); profile != nil {
+			snapshot.profiles[profileName] = maps.Clone(profile.Mods)
+		} else {
+			snapshot.missingProfiles = append(snapshot.missingProfiles, profileName)
+		}
+	}
+	for _, installPath := range scope.installs {
+		if installation := f.ficsitCli.Installations.GetInstallation(installPath); installation != nil {
+			installations = append(installations, installation)
+			snapshot.installs[installPath] = installSnapshot{
+				profile: installation.Profile,
+				vanilla: installation.Vanilla,
+			}
+		}
+	}
+	f.stateMutex.Unlock()
+
+	for _, installation := range installations {
+		lockfile, err := installation.LockFile(f.ficsitCli)
+		if err != nil {
+			slog.Warn("failed to read lockfile for undo", slog.String("install", installation.Path), slog.Any("error", err))
+			delete(snapshot.installs, installation.Path)
+			continue
+		}
+		if lockfile != nil {
+			lockfile = lockfile.Clone()
+		}
+		installState := snapshot.installs[installation.Path]
+		installState.lockfile = lockfile
+		snapshot.installs[installation.Path] = installState
+	}
+
+	return snapshot
+}
+
+// snapshotChanged returns whether the state of the snapshot's scope is no longer the one it recorded
+func (f *ficsitCLI) snapshotChanged(snapshot *undoSnapshot) bool {
+	scope := actionScope{}
+	for profileName := range snapshot.profiles {
+		scope.profiles = append(scope.profiles, profileName)
+	}
+	scope.profiles = append(scope.profiles, snapshot.missingProfiles...)
+	for installPath := range snapshot.installs {
+		scope.installs = append(scope.installs, installPath)
+	}
+	current := f.snapshotScope(snapshot.Action, snapshot.Item, scope)
+
+	if len(current.missingProfiles) != len(snapshot.missingProfiles) {
+		return true
+	}
+	for profileName, mods := range snapshot.profiles {
+		currentMods, ok := current.profiles[profileName]
+		if !ok || !maps.Equal(mods, currentMods) {
+			return true
+		}
+	}
+	for installPath, installState := range snapshot.installs {
+		currentState, ok := current.installs[installPath]
+		if !ok || currentState.profile != installState.profile || currentState.vanilla != installState.vanilla {
+			return true
+		}
+		if !reflect.DeepEqual(currentState.lockfile, installState.lockfile) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordUndo adds the snapshot to the undo history, if the action changed something.
+// Failed actions are recorded too, since a failed apply may leave the profile or lockfile changed
+func (f *ficsitCLI) recordUndo(snapshot *undoSnapshot, actionErr error) {
+	if snapshot == nil {
+		return
+	}
+	if !f.snapshotChanged(snapshot) {
+		return
+	}
+	snapshot.Failed = actionErr != nil
+	snapshot.Time = time.Now().UTC()
+
+	f.undoHistoryMutex.Lock()
+	for _, key := range snapshot.historyKeys() {
+		history := append(f.undoHistories[key], snapshot)
+		if len(history) > undoHistorySize {
+			history = history[len(history)-undoHistorySize:]
+		}
+		f.undoHistories[key] = history
+	}
+	f.undoHistoryMutex.Unlock()
+
+	f.emitUndoHistory()
+}
+
+// viewedUndoHistory returns the snapshots of the selected install and its profile, oldest first.
+// The undo history mutex must be held
+func (f *ficsitCLI) viewedUndoHistory() []*undoSnapshot {
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return nil
+	}
+
+	var snapshots []*undoSnapshot
+	for _, key := range []string{installHistoryKey(selectedInstallation.Path), profileHistoryKey(selectedInstallation.Profile)} {
+		for _, snapshot := range f.undoHistories[key] {
+			if !slices.Contains(snapshots, snapshot) {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+	}
+	slices.SortStableFunc(snapshots, func(a, b *undoSnapshot) int {
+		return a.Time.Compare(b.Time)
+	})
+	return snapshots
+}
+
+// GetUndoHistory returns the actions on the selected install that can be undone, most recent first
+func (f *ficsitCLI) GetUndoHistory() []UndoEntry {
+	f.undoHistoryMutex.Lock()
+	defer f.undoHistoryMutex.Unlock()
+
+	snapshots := f.viewedUndoHistory()
+	entries := make([]UndoEntry, 0, len(snapshots))
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		entries = append(entries, snapshots[i].UndoEntry)
+	}
+	return entries
+}
+
+func (f *ficsitCLI) emitUndoHistory() {
+	if common.AppContext == nil {
+		return
+	}
+	wailsRuntime.EventsEmit(common.AppContext, "undoHistory", f.GetUndoHistory())
+}
+
+// Undo restores the profiles and lockfiles from before the last action on the selected install, and applies them
+func (f *ficsitCLI) Undo() error {
+	f.undoHistoryMutex.Lock()
+	snapshots := f.viewedUndoHistory()
+	if len(snapshots) == 0 {
+		f.undoHistoryMutex.Unlock()
+		return fmt.Errorf("nothing to undo")
+	}
+	snapshot := snapshots[len(snapshots)-1]
+	f.undoHistoryMutex.Unlock()
+
+	var scope actionScope
+	for profileName := range snapshot.profiles {
+		scope = scope.with(profileScope(profileName))
+	}
+	for _, profileName := range snapshot.missingProfiles {
+		scope = scope.with(profileScope(profileName))
+	}
+	for installPath, installState := range snapshot.installs {
+		scope = scope.with(installScope(installPath)).with(profileScope(installState.profile))
+		if installation := f.GetInstallation(installPath); installation != nil {
+			scope = scope.with(profileScope(installation.Profile))
+		}
+	}
+
+	var deletedProfiles []string
+	err := f.action(ActionUndo, newSimpleItem(string(snapshot.Action)), scope, func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		defer close(taskChannel)
+
+		l = l.With(slog.String("undo", snapshot.ID))
+
+		var err error
+		deletedProfiles, err = f.restoreSnapshot(l, snapshot)
+		if err != nil {
+			return err
+		}
+
+		f.EmitGlobals()
+		f.EmitModsChange()
+		defer f.EmitModsChange()
+
+		// Re-apply every profile that changed, through one of the installs using it
+		var installsToApply []installWithTarget
+		appliedProfiles := make(map[string]bool)
+		for installPath := range snapshot.installs {
+			installation := f.GetInstallation(installPath)
+			if installation == nil || appliedProfiles[installation.Profile] {
+				continue
+			}
+			appliedProfiles[installation.Profile] = true
+			profileInstalls, _, err := f.getInstallsToApply(installation)
+			if err != nil {
+				l.Warn("failed to get installs to apply", slog.String("install", installPath), slog.Any("error", err))
+				continue
+			}
+			installsToApply = append(installsToApply, profileInstalls...)
+		}
+
+		return f.installTargets(installsToApply, taskChannel)
+	})
+
+	for _, profileName := range deletedProfiles {
+		f.deleteProfileMetadata(profileName)
+		f.subscriptionUpdates.Delete(profileName)
+	}
+	if len(deletedProfiles) > 0 {
+		f.EmitGlobals()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	f.undoHistoryMutex.Lock()
+	for key, history := range f.undoHistories {
+		f.undoHistories[key] = slices.DeleteFunc(history, func(s *undoSnapshot) bool {
+			return s == snapshot
+		})
+	}
+	f.undoHistoryMutex.Unlock()
+
+	f.emitUndoHistory()
+
+	return nil
+}
+
+// restoreSnapshot restores the profiles and installs of the snapshot, and deletes the profiles the action created.
+// Returns the deleted profiles, whose metadata must be deleted without holding the state lock
+func (f *ficsitCLI) restoreSnapshot(l *slog.Logger, snapshot *undoSnapshot) ([]string, error) {
+	var restoredProfiles []string
+	var deletedProfiles []string
+	err := f.updateState(func() error {
+		for profileName, mods := range snapshot.profiles {
+			profile := f.GetProfile(profileName)
+			if profile == nil {
+				l.Warn("profile no longer exists", slog.String("profile", profileName))
+				continue
+			}
+			profile.Mods = maps.Clone(mods)
+			restoredProfiles = append(restoredProfiles, profileName)
+		}
+		err := f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profiles", slog.Any("error", err))
+		}
+
+		for installPath, installState := range snapshot.installs {
+			installation := f.GetInstallation(installPath)
+			if installation == nil {
+				l.Warn("install no longer exists", slog.String("install", installPath))
+				continue
+			}
+			if installation.Profile != installState.profile {
+				if f.GetProfile(installState.profile) == nil {
+					l.Warn("previous profile no longer exists", slog.String("install", installPath), slog.String("profile", installState.profile))
+				} else if err := installation.SetProfile(f.ficsitCli, installState.profile); err != nil {
+					return fmt.Errorf("failed to restore profile of %s: %w", installPath, err)
+				}
+			}
+			installation.Vanilla = installState.vanilla
+		}
+		err = f.ficsitCli.Installations.Save()
+		if err != nil {
+			l.Error("failed to save installations", slog.Any("error", err))
+		}
+
+		// Profiles the action created, such as imported ones, are deleted once no install uses them anymore
+		for _, profileName := range snapshot.missingProfiles {
+			if f.ficsitCli.Profiles.GetProfile(profileName) == nil {
+				continue
+			}
+			inUse := slices.ContainsFunc(f.ficsitCli.Installations.Installations, func(installation *cli.Installation) bool {
+				return installation.Profile == profileName
+			})
+			if inUse {
+				l.Warn("created profile is still used, not deleting it", slog.String("profile", profileName))
+				continue
+			}
+			if err := f.ficsitCli.Profiles.DeleteProfile(profileName); err != nil {
+				l.Error("failed to delete created profile", slog.String("profile", profileName), slog.Any("error", err))
+				continue
+			}
+			deletedProfiles = append(deletedProfiles, profileName)
+		}
+		if len(deletedProfiles) > 0 {
+			err = f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profiles", slog.Any("error", err))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profileName := range restoredProfiles {
+		f.markProfileModified(profileName)
+	}
+
+	for installPath, installState := range snapshot.installs {
+		installation := f.GetInstallation(installPath)
+		if installation == nil {
+			continue
+		}
+		lockfile := installState.lockfile
+		if lockfile == nil {
+			lockfile = resolver.NewLockfile()
+		}
+		if err := installation.WriteLockFile(f.ficsitCli, lockfile); err != nil {
+			return deletedProfiles, fmt.Errorf("failed to restore lockfile of %s: %w", installPath, err)
+		}
+	}
+
+	return deletedProfiles, nil
+}
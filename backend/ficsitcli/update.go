@@ -4,15 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 
 	"github.com/satisfactorymodding/ficsit-cli/cli"
 	resolver "github.com/satisfactorymodding/ficsit-resolver"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
 )
 
 type Update struct {
 	Item           string `json:"item"`
 	CurrentVersion string `json:"currentVersion"`
 	NewVersion     string `json:"newVersion"`
+	// Ignored is set when an ignore rule matches the update on the checked install and profile
+	Ignored bool `json:"ignored"`
+	// IgnoreRules are the IDs of the rules that ignore the update, removing them unignores it
+	IgnoreRules []string `json:"ignoreRules,omitempty"`
+	// Changelogs of the versions after CurrentVersion, up to and including NewVersion, newest first
+	Changelogs        []VersionChangelog `json:"changelogs,omitempty"`
+	DependencyChanges []DependencyChange `json:"dependencyChanges,omitempty"`
 }
 
 func (f *ficsitCLI) CheckForUpdates() ([]Update, error) {
@@ -23,7 +33,15 @@ func (f *ficsitCLI) CheckForUpdates() ([]Update, error) {
 	}
 	l := slog.With(slog.String("task", "checkForUpdates"))
 
-	currentLockfile, err := selectedInstallation.LockFile(f.ficsitCli)
+	settings.Settings.CleanupIgnoreRules()
+
+	return f.checkInstallForUpdates(l, selectedInstallation)
+}
+
+// checkInstallForUpdates resolves the latest versions of the install's profile mods,
+// and returns the ones that differ from the install's lockfile
+func (f *ficsitCLI) checkInstallForUpdates(l *slog.Logger, installation *cli.Installation) ([]Update, error) {
+	currentLockfile, err := installation.LockFile(f.ficsitCli)
 	if err != nil {
 		l.Error("failed to get current lockfile", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to get current lockfile: %w", err)
@@ -33,11 +51,18 @@ func (f *ficsitCLI) CheckForUpdates() ([]Update, error) {
 		return nil, nil
 	}
 
-	profile := f.GetProfile(selectedInstallation.Profile)
+	// Copied while holding the state lock, since actions can be changing the profile while this runs in the background
+	f.stateMutex.Lock()
+	profileName := installation.Profile
+	f.stateMutex.Unlock()
+	profile := f.copyProfile(profileName)
+	if profile == nil {
+		return nil, fmt.Errorf("profile not found: %s", profileName)
+	}
 
 	res := resolver.NewDependencyResolver(f.ficsitCli.Provider)
 
-	gameVersion, err := selectedInstallation.GetGameVersion(f.ficsitCli)
+	gameVersion, err := installation.GetGameVersion(f.ficsitCli)
 	if err != nil {
 		l.Error("failed to get game version", slog.Any("error", err))
 		return nil, fmt.Errorf("failed to get game version: %w", err)
@@ -68,44 +93,58 @@ func (f *ficsitCLI) CheckForUpdates() ([]Update, error) {
 	for modReference, newLockedMod := range newLockfile.Mods {
 		if prevLockedMod, ok := currentLockfile.Mods[modReference]; ok {
 			if newLockedMod.Version != prevLockedMod.Version {
-				updates = append(updates, Update{
+				update := Update{
 					Item:           modReference,
 					CurrentVersion: prevLockedMod.Version,
 					NewVersion:     newLockedMod.Version,
-				})
+				}
+				update.IgnoreRules = settings.Settings.MatchingIgnoreRules(modReference, newLockedMod.Version, installation.Path, profileName)
+				update.Ignored = len(update.IgnoreRules) > 0
+				updates = append(updates, update)
 			}
 		}
 	}
 
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].Item < updates[j].Item
+	})
+
+	f.addUpdateDetails(l, updates, currentLockfile)
+
 	return updates, nil
 }
 
 func (f *ficsitCLI) UpdateMods(mods []string) error {
-	return f.action(ActionUpdate, noItem, func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
-		selectedInstallation := f.GetSelectedInstall()
+	selectedInstallation := f.GetSelectedInstall()
+	if selectedInstallation == nil {
+		return fmt.Errorf("no installation selected")
+	}
 
-		if selectedInstallation == nil {
-			return fmt.Errorf("no installation selected")
-		}
+	return f.action(ActionUpdate, noItem, installProfileScope(selectedInstallation), func(l *slog.Logger, taskUpdates chan<- taskUpdate) error {
 
 		profile := f.GetProfile(selectedInstallation.Profile)
-		for _, modReference := range mods {
-			if _, ok := profile.Mods[modReference]; !ok {
-				l.Warn("mod not found in profile", slog.String("mod", modReference))
-				continue
+		_ = f.updateState(func() error {
+			for _, modReference := range mods {
+				if _, ok := profile.Mods[modReference]; !ok {
+					l.Warn("mod not found in profile", slog.String("mod", modReference))
+					continue
+				}
+				profile.Mods[modReference] = cli.ProfileMod{
+					Enabled: profile.Mods[modReference].Enabled,
+					Version: ">=0.0.0",
+				}
 			}
-			profile.Mods[modReference] = cli.ProfileMod{
-				Enabled: profile.Mods[modReference].Enabled,
-				Version: ">=0.0.0",
+
+			err := f.ficsitCli.Profiles.Save()
+			if err != nil {
+				l.Error("failed to save profile", slog.Any("error", err))
 			}
-		}
+			return nil
+		})
 
-		err := f.ficsitCli.Profiles.Save()
-		if err != nil {
-			l.Error("failed to save profile", slog.Any("error", err))
-		}
+		f.markProfileModified(selectedInstallation.Profile)
 
-		err = selectedInstallation.UpdateMods(f.ficsitCli, mods)
+		err := selectedInstallation.UpdateMods(f.ficsitCli, mods)
 		if err != nil {
 			l.Error("failed to update mods", slog.Any("error", err))
 			var solvingError resolver.DependencyResolverError
@@ -115,7 +154,7 @@ func (f *ficsitCLI) UpdateMods(mods []string) error {
 			return err //nolint:wrapcheck
 		}
 
-		err = f.apply(l, taskUpdates)
+		err = f.apply(l, selectedInstallation, taskUpdates)
 		if err != nil {
 			l.Error("failed to validate installation", slog.Any("error", err))
 			return err
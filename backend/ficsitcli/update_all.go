@@ -0,0 +1,278 @@
+package ficsitcli
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	appCommon "github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+type InstallUpdates struct {
+	Install string   `json:"install"`
+	Profile string   `json:"profile"`
+	Updates []Update `json:"updates"`
+	// GameRunning is only known for local installs, remote ones are always reported as not running
+	GameRunning bool   `json:"gameRunning"`
+	Error       string `json:"error,omitempty"`
+}
+
+type UpdatesReport struct {
+	Installs []InstallUpdates `json:"installs"`
+	Checked  time.Time        `json:"checked"`
+}
+
+const updatesCheckInterval = 30 * time.Minute
+
+// isGameRunningOn returns whether the game is running from the local install. When a running game's executable
+// is not in any known local install, such as with Wine, it is assumed to be running on every local install
+func (f *ficsitCLI) isGameRunningOn(path string) bool {
+	metadata, ok := f.installationMetadata.Load(path)
+	if !ok || metadata.Info == nil || metadata.Info.Location != common.LocationTypeLocal {
+		return false
+	}
+
+	var localInstalls []string
+	f.installationMetadata.Range(func(installPath string, installMetadata installationMetadata) bool {
+		if installMetadata.Info != nil && installMetadata.Info.Location == common.LocationTypeLocal {
+			localInstalls = append(localInstalls, installPath)
+		}
+		return true
+	})
+
+	for _, executable := range f.getRunningGameExecutables() {
+		if executable == "" {
+			return true
+		}
+		if pathWithin(executable, path) {
+			return true
+		}
+		inKnownInstall := slices.ContainsFunc(localInstalls, func(installPath string) bool {
+			return pathWithin(executable, installPath)
+		})
+		if !inKnownInstall {
+			return true
+		}
+	}
+	return false
+}
+
+func pathWithin(path string, dir string) bool {
+	if runtime.GOOS == "windows" {
+		path = strings.ToLower(path)
+		dir = strings.ToLower(dir)
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// CheckAllForUpdates checks every valid, modded install for updates of its profile's mods, excluding ignored updates.
+// Profiles are checked through the installs using them, since resolving depends on the install's game version
+func (f *ficsitCLI) CheckAllForUpdates() *UpdatesReport {
+	l := slog.With(slog.String("task", "checkAllForUpdates"))
+
+	settings.Settings.CleanupIgnoreRules()
+
+	report := &UpdatesReport{
+		Installs: []InstallUpdates{},
+		Checked:  time.Now().UTC(),
+	}
+
+	f.stateMutex.Lock()
+	installations := slices.Clone(f.ficsitCli.Installations.Installations)
+	f.stateMutex.Unlock()
+
+	for _, installation := range installations {
+		if installation.Vanilla || !f.isValidInstall(installation.Path) {
+			continue
+		}
+		metadata, ok := f.installationMetadata.Load(installation.Path)
+		if !ok || metadata.State != InstallStateValid {
+			continue
+		}
+
+		installUpdates := InstallUpdates{
+			Install:     installation.Path,
+			Profile:     installation.Profile,
+			Updates:     []Update{},
+			GameRunning: f.isGameRunningOn(installation.Path),
+		}
+
+		updates, err := f.checkInstallForUpdates(l.With(slog.String("install", installation.Path)), installation)
+		if err != nil {
+			installUpdates.Error = err.Error()
+		}
+		for _, update := range updates {
+			if !update.Ignored {
+				installUpdates.Updates = append(installUpdates.Updates, update)
+			}
+		}
+		sort.Slice(installUpdates.Updates, func(i, j int) bool {
+			return installUpdates.Updates[i].Item < installUpdates.Updates[j].Item
+		})
+
+		report.Installs = append(report.Installs, installUpdates)
+	}
+
+	sort.Slice(report.Installs, func(i, j int) bool {
+		return report.Installs[i].Install < report.Installs[j].Install
+	})
+
+	f.updatesReportMutex.Lock()
+	f.updatesReport = report
+	f.updatesReportMutex.Unlock()
+
+	if appCommon.AppContext != nil {
+		wailsRuntime.EventsEmit(appCommon.AppContext, "updatesReport", report)
+	}
+
+	return report
+}
+
+// GetUpdatesReport returns the result of the last update check across all installs, or nil if none ran yet
+func (f *ficsitCLI) GetUpdatesReport() *UpdatesReport {
+	f.updatesReportMutex.Lock()
+	defer f.updatesReportMutex.Unlock()
+	return f.updatesReport
+}
+
+func (f *ficsitCLI) StartUpdatesCheckWatcher() {
+	go func() {
+		f.CheckAllForUpdates()
+
+		ticker := time.NewTicker(updatesCheckInterval)
+		for range ticker.C {
+			f.CheckAllForUpdates()
+		}
+	}()
+}
+
+// UpdateAll applies the available updates install by install, skipping the installs where the game is running
+func (f *ficsitCLI) UpdateAll() error {
+	report := f.CheckAllForUpdates()
+
+	var scope actionScope
+	for _, installUpdates := range report.Installs {
+		if len(installUpdates.Updates) > 0 && !installUpdates.GameRunning {
+			scope = scope.with(installScope(installUpdates.Install)).with(profileScope(installUpdates.Profile))
+		}
+	}
+	if len(scope.profiles) == 0 {
+		// Nothing to update
+		return nil
+	}
+
+	return f.action(ActionUpdateAll, noItem, scope, func(l *slog.Logger, taskChannel chan<- taskUpdate) error {
+		defer close(taskChannel)
+
+		updatedInstalls := make(map[string]bool)
+		for _, installUpdates := range report.Installs {
+			if len(installUpdates.Updates) > 0 && !installUpdates.GameRunning {
+				updatedInstalls[installUpdates.Install] = true
+			}
+		}
+
+		var errs []error
+		for _, installUpdates := range report.Installs {
+			if len(installUpdates.Updates) == 0 {
+				continue
+			}
+			installLogger := l.With(slog.String("install", installUpdates.Install))
+			if installUpdates.GameRunning {
+				installLogger.Info("game is running, skipping install")
+				continue
+			}
+			err := f.updateInstall(installLogger, f.GetInstallation(installUpdates.Install), installUpdates.Updates, updatedInstalls, taskChannel)
+			if err != nil {
+				installLogger.Error("failed to update install", slog.Any("error", err))
+				errs = append(errs, fmt.Errorf("%s: %w", installUpdates.Install, err))
+			}
+		}
+
+		f.EmitModsChange()
+		f.CheckAllForUpdates()
+
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to update some installs: %v", errs)
+		}
+		return nil
+	})
+}
+
+// updateInstall updates the given mods of the install, the same way UpdateMods does for the selected install.
+// The version constraints of the profile are only relaxed if all the installs using it are updated,
+// otherwise the update stays within the constraints, so it does not change what the other installs may resolve to
+func (f *ficsitCLI) updateInstall(l *slog.Logger, installation *cli.Installation, updates []Update, updatedInstalls map[string]bool, taskChannel chan<- taskUpdate) error {
+	if installation == nil {
+		return fmt.Errorf("installation not found")
+	}
+
+	profile := f.GetProfile(installation.Profile)
+	if profile == nil {
+		return fmt.Errorf("profile not found: %s", installation.Profile)
+	}
+
+	mods := make([]string, 0, len(updates))
+	for _, update := range updates {
+		mods = append(mods, update.Item)
+	}
+
+	relaxConstraints := true
+	_ = f.updateState(func() error {
+		for _, other := range f.ficsitCli.Installations.Installations {
+			if other.Profile == installation.Profile && !updatedInstalls[other.Path] {
+				relaxConstraints = false
+				break
+			}
+		}
+		if !relaxConstraints {
+			return nil
+		}
+
+		for _, update := range updates {
+			profileMod, ok := profile.Mods[update.Item]
+			if !ok {
+				// Dependency, not in the profile
+				continue
+			}
+			profile.Mods[update.Item] = cli.ProfileMod{
+				Enabled: profileMod.Enabled,
+				Version: ">=0.0.0",
+			}
+		}
+
+		err := f.ficsitCli.Profiles.Save()
+		if err != nil {
+			l.Error("failed to save profile", slog.Any("error", err))
+		}
+		return nil
+	})
+
+	if relaxConstraints {
+		f.markProfileModified(installation.Profile)
+	} else {
+		l.Info("profile is shared with installs that are not updated, keeping its version constraints")
+	}
+
+	err := installation.UpdateMods(f.ficsitCli, mods)
+	if err != nil {
+		return fmt.Errorf("failed to update mods: %w", err)
+	}
+
+	platform, err := installation.GetPlatform(f.ficsitCli)
+	if err != nil {
+		return fmt.Errorf("failed to get platform: %w", err)
+	}
+
+	return f.installTargets([]installWithTarget{{install: installation, targetName: platform.TargetName}}, taskChannel)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/satisfactorymodding/ficsit-cli/cli"
 	"github.com/satisfactorymodding/ficsit-cli/cli/provider"
+	psUtilProcess "github.com/shirou/gopsutil/v3/process"
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 
 	appCommon "github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
@@ -22,8 +23,26 @@ type ficsitCLI struct {
 	ficsitCli            *cli.GlobalContext
 	installationMetadata *xsync.MapOf[string, installationMetadata]
 	installFindErrors    []error
-	isGameRunning        bool
-	actionMutex          sync.Mutex
+	profileTemplates     *profileTemplates
+	profilesMetadata     *profilesMetadata
+	subscriptionUpdates  *xsync.MapOf[string, *ProfileSubscriptionUpdate]
+	// runningGameExecutables are the paths of the running game processes, empty when the path is unknown
+	runningGameExecutables []string
+	gameRunningMutex       sync.Mutex
+	runningActions         []*Progress
+	progressMutex          sync.Mutex
+	// actionLocks are the locks of the installs and profiles in the scope of running actions
+	actionLocks *xsync.MapOf[string, *sync.Mutex]
+	// stateMutex guards the ficsit-cli profiles and installations, and the profiles metadata
+	stateMutex     sync.Mutex
+	installWatcher *installWatcher
+	// updatesReport is the result of the last update check across all installs
+	updatesReport      *UpdatesReport
+	updatesReportMutex sync.Mutex
+	// undoHistories holds the undo snapshots by history key, so undoing only affects the install in view.
+	// A snapshot is in the history of every install in its scope, or of its profiles if no install uses them
+	undoHistories    map[string][]*undoSnapshot
+	undoHistoryMutex sync.Mutex
 }
 
 var FicsitCLI *ficsitCLI
@@ -38,7 +57,25 @@ func Init() error {
 	}
 	ficsitCli.Provider.(*provider.MixedProvider).Offline = settings.Settings.Offline
 
-	FicsitCLI = &ficsitCLI{ficsitCli: ficsitCli, installationMetadata: xsync.NewMapOf[string, installationMetadata]()}
+	templates, err := loadProfileTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to load profile templates: %w", err)
+	}
+
+	profilesMetadata, err := loadProfilesMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to load profile metadata: %w", err)
+	}
+
+	FicsitCLI = &ficsitCLI{
+		ficsitCli:            ficsitCli,
+		installationMetadata: xsync.NewMapOf[string, installationMetadata](),
+		profileTemplates:     templates,
+		profilesMetadata:     profilesMetadata,
+		subscriptionUpdates:  xsync.NewMapOf[string, *ProfileSubscriptionUpdate](),
+		actionLocks:          xsync.NewMapOf[string, *sync.Mutex](),
+		undoHistories:        make(map[string][]*undoSnapshot),
+	}
 	err = FicsitCLI.initInstallations()
 	if err != nil {
 		return fmt.Errorf("failed to initialize installations: %w", err)
@@ -81,18 +118,39 @@ func (f *ficsitCLI) StartGameRunningWatcher() {
 				slog.Error("failed to get processes", slog.Any("error", err))
 				continue
 			}
-			f.isGameRunning = false
+			var runningExecutables []string
 			for _, process := range processes {
 				if slices.Contains(executableNames, process.Executable()) {
-					f.isGameRunning = true
-					break
+					runningExecutables = append(runningExecutables, processExecutablePath(process.Pid()))
 				}
 			}
-			wailsRuntime.EventsEmit(appCommon.AppContext, "isGameRunning", f.isGameRunning)
+			f.gameRunningMutex.Lock()
+			f.runningGameExecutables = runningExecutables
+			f.gameRunningMutex.Unlock()
+			wailsRuntime.EventsEmit(appCommon.AppContext, "isGameRunning", len(runningExecutables) > 0)
 		}
 	}()
 }
 
+// processExecutablePath returns the path of the process executable, or an empty string if it cannot be read
+func processExecutablePath(pid int) string {
+	process, err := psUtilProcess.NewProcess(int32(pid))
+	if err != nil {
+		return ""
+	}
+	executable, err := process.Exe()
+	if err != nil {
+		return ""
+	}
+	return executable
+}
+
+func (f *ficsitCLI) getRunningGameExecutables() []string {
+	f.gameRunningMutex.Lock()
+	defer f.gameRunningMutex.Unlock()
+	return f.runningGameExecutables
+}
+
 // GetProgress exists only to ensure the Progress type is exported to typescript. It returns nil
 func (f *ficsitCLI) GetProgress() *Progress {
 	return nil
@@ -118,11 +176,13 @@ func (f *ficsitCLI) EmitGlobals() {
 	wailsRuntime.EventsEmit(appCommon.AppContext, "installations", f.GetInstallations())
 	wailsRuntime.EventsEmit(appCommon.AppContext, "installationsMetadata", f.GetInstallationsMetadata())
 	wailsRuntime.EventsEmit(appCommon.AppContext, "remoteServers", f.GetRemoteInstallations())
-	profileNames := make([]string, 0, len(f.ficsitCli.Profiles.Profiles))
-	for k := range f.ficsitCli.Profiles.Profiles {
-		profileNames = append(profileNames, k)
-	}
-	wailsRuntime.EventsEmit(appCommon.AppContext, "profiles", profileNames)
+	wailsRuntime.EventsEmit(appCommon.AppContext, "localServers", f.GetLocalServers())
+	wailsRuntime.EventsEmit(appCommon.AppContext, "profiles", f.GetProfiles())
+	wailsRuntime.EventsEmit(appCommon.AppContext, "profileTemplates", f.GetProfileTemplates())
+	wailsRuntime.EventsEmit(appCommon.AppContext, "profilesMetadata", f.GetProfilesMetadata())
+	wailsRuntime.EventsEmit(appCommon.AppContext, "profileSubscriptionUpdates", f.GetProfileSubscriptionUpdates())
+	// The undo history shown is the one of the selected install
+	f.emitUndoHistory()
 
 	selectedInstallation := f.GetSelectedInstall()
 
@@ -134,10 +194,23 @@ func (f *ficsitCLI) EmitGlobals() {
 	wailsRuntime.EventsEmit(appCommon.AppContext, "selectedProfile", selectedInstallation.Profile)
 	wailsRuntime.EventsEmit(appCommon.AppContext, "modsEnabled", !selectedInstallation.Vanilla)
 	wailsRuntime.EventsEmit(appCommon.AppContext, "selectedProfileTargets", f.SelectedProfileTargets())
+
+	profileInstallMismatch := f.GetSelectedProfileInstallMismatch()
+	if profileInstallMismatch != nil {
+		slog.Warn(
+			"selected profile was made for a different game branch or version",
+			slog.String("profile", profileInstallMismatch.Profile),
+			slog.String("profileBranch", string(profileInstallMismatch.ProfileBranch)),
+			slog.String("installBranch", string(profileInstallMismatch.InstallBranch)),
+			slog.Int("profileGameVersion", profileInstallMismatch.ProfileGameVersion),
+			slog.Int("installGameVersion", profileInstallMismatch.InstallGameVersion),
+		)
+	}
+	wailsRuntime.EventsEmit(appCommon.AppContext, "profileInstallMismatch", profileInstallMismatch)
 }
 
 func (f *ficsitCLI) SelectedProfileTargets() map[string][]string {
-	installsWithTargets, _, err := f.getInstallsToApply()
+	installsWithTargets, _, err := f.getInstallsToApply(f.GetSelectedInstall())
 	if err != nil {
 		slog.Error("failed to get installs to apply", slog.Any("error", err))
 		return nil
@@ -191,9 +264,15 @@ func (f *ficsitCLI) WipeMods(includeRemote bool) error {
 // AddInstallation adds a new installation to ficsit-cli and registers it in the metadata
 func (f *ficsitCLI) AddInstallation(path string, launchPath []string, installType string, branch string, version int, launcher string) error {
 	// Add to ficsit-cli installations
-	_, err := f.ficsitCli.Installations.AddInstallation(f.ficsitCli, path, f.GetFallbackProfile())
+	err := f.updateState(func() error {
+		_, err := f.ficsitCli.Installations.AddInstallation(f.ficsitCli, path, f.GetFallbackProfile())
+		if err != nil {
+			return fmt.Errorf("failed to add installation to ficsit-cli: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to add installation to ficsit-cli: %w", err)
+		return err
 	}
 
 	// Create installation entry for metadata
@@ -212,11 +291,18 @@ func (f *ficsitCLI) AddInstallation(path string, launchPath []string, installTyp
 		State: InstallStateValid,
 		Info:  installation,
 	})
+	f.watchInstallation(path)
 
 	// Save installations
-	err = f.ficsitCli.Installations.Save()
+	err = f.updateState(func() error {
+		err := f.ficsitCli.Installations.Save()
+		if err != nil {
+			return fmt.Errorf("failed to save installations: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save installations: %w", err)
+		return err
 	}
 
 	// Emit globals to update frontend
@@ -237,17 +323,23 @@ func (f *ficsitCLI) EnsureSelectedInstallationIsValid() {
 
 // ClearInstallations removes all registered installations
 func (f *ficsitCLI) ClearInstallations() error {
-	// Clear all installations from ficsit-cli
-	f.ficsitCli.Installations.Installations = make([]*cli.Installation, 0)
-	f.ficsitCli.Installations.SelectedInstallation = ""
-	
-	// Clear all installation metadata
-	f.installationMetadata.Clear()
-	
-	// Save the empty installations list
-	err := f.ficsitCli.Installations.Save()
+	err := f.updateState(func() error {
+		// Clear all installations from ficsit-cli
+		f.ficsitCli.Installations.Installations = make([]*cli.Installation, 0)
+		f.ficsitCli.Installations.SelectedInstallation = ""
+
+		// Clear all installation metadata
+		f.installationMetadata.Clear()
+
+		// Save the empty installations list
+		err := f.ficsitCli.Installations.Save()
+		if err != nil {
+			return fmt.Errorf("failed to save empty installations: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to save empty installations: %w", err)
+		return err
 	}
 	
 	// Emit globals to update frontend
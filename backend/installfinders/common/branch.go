@@ -0,0 +1,170 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/andygrunwald/vdf"
+	"github.com/satisfactorymodding/ficsit-cli/cli/disk"
+)
+
+var (
+	EpicEarlyAccessAppName                 = "CrabEA"
+	EpicExperimentalAppName                = "CrabTest"
+	EpicEarlyAccessDedicatedServerAppName  = "CrabDedicatedServer"
+	EpicExperimentalDedicatedServerAppName = "c509233193024c5f8124467d3aa36199"
+)
+
+var steamManifests = []string{"appmanifest_526870.acf", "appmanifest_1690800.acf"}
+
+func GetEpicBranch(appName string) (GameBranch, error) {
+	switch appName {
+	case EpicEarlyAccessAppName:
+		return BranchStable, nil
+	case EpicExperimentalAppName:
+		return BranchExperimental, nil
+	case EpicEarlyAccessDedicatedServerAppName:
+		return BranchStable, nil
+	case EpicExperimentalDedicatedServerAppName:
+		return BranchExperimental, nil
+	default:
+		return "", fmt.Errorf("unknown branch for %s", appName)
+	}
+}
+
+// GetSteamBranch returns the branch from the beta key of a Steam app manifest's AppState
+func GetSteamBranch(appState map[string]interface{}) (GameBranch, error) {
+	var betaKey string
+	for _, configKey := range []string{"UserConfig", "MountedConfig"} {
+		config, ok := appState[configKey].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range config {
+			if strings.EqualFold(key, "betakey") {
+				betaKey, _ = value.(string)
+			}
+		}
+		if betaKey != "" {
+			break
+		}
+	}
+
+	switch strings.ToLower(betaKey) {
+	case "", "public":
+		return BranchStable, nil
+	case "experimental":
+		return BranchExperimental, nil
+	default:
+		return "", fmt.Errorf("unknown beta key %s", betaKey)
+	}
+}
+
+// DetectBranch finds the branch of the install from the files in its directory: the Steam manifest SteamCMD writes
+// with force_install_dir, the Epic manifest in .egstore, and finally the version file.
+// It reads through the disk, so it also works for remote installs
+func DetectBranch(d disk.Disk, installPath string) (GameBranch, error) {
+	if branch, ok := branchFromSteamManifest(d, installPath); ok {
+		return branch, nil
+	}
+	if branch, ok := branchFromEpicManifest(d, installPath); ok {
+		return branch, nil
+	}
+	if branch, ok := branchFromVersionFile(d, installPath); ok {
+		return branch, nil
+	}
+	return "", fmt.Errorf("no manifest or version file identifies the branch")
+}
+
+// DetectLocalBranch detects the branch of a local install, defaulting to stable if it cannot be detected
+func DetectLocalBranch(installPath string) GameBranch {
+	d, err := disk.FromPath(installPath)
+	if err == nil {
+		var branch GameBranch
+		branch, err = DetectBranch(d, installPath)
+		if err == nil {
+			return branch
+		}
+	}
+	slog.Warn("failed to detect branch, assuming stable", slog.String("path", installPath), slog.Any("error", err))
+	return BranchStable
+}
+
+func branchFromSteamManifest(d disk.Disk, installPath string) (GameBranch, bool) {
+	for _, manifest := range steamManifests {
+		data, err := d.Read(filepath.Join(installPath, "steamapps", manifest))
+		if err != nil {
+			continue
+		}
+		parsed, err := vdf.NewParser(bytes.NewReader(data)).Parse()
+		if err != nil {
+			continue
+		}
+		appState, ok := parsed["AppState"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		branch, err := GetSteamBranch(appState)
+		if err == nil {
+			return branch, true
+		}
+	}
+	return "", false
+}
+
+func branchFromEpicManifest(d disk.Disk, installPath string) (GameBranch, bool) {
+	egstorePath := filepath.Join(installPath, ".egstore")
+	entries, err := d.ReadDir(egstorePath)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".mancpn") {
+			continue
+		}
+		data, err := d.Read(filepath.Join(egstorePath, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var manifest struct {
+			AppName string `json:"AppName"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			continue
+		}
+		branch, err := GetEpicBranch(manifest.AppName)
+		if err == nil {
+			return branch, true
+		}
+	}
+	return "", false
+}
+
+// branchFromVersionFile only recognizes branch names and build IDs that name the branch,
+// since the others are not distinctive enough to tell the branches apart
+func branchFromVersionFile(d disk.Disk, installPath string) (GameBranch, bool) {
+	for _, versionFilePath := range VersionFilePaths(installPath) {
+		data, err := d.Read(versionFilePath)
+		if err != nil {
+			continue
+		}
+		var versionData GameVersionFile
+		if err := json.Unmarshal(data, &versionData); err != nil {
+			continue
+		}
+		for _, name := range []string{versionData.BranchName, versionData.BuildID} {
+			name = strings.ToLower(name)
+			switch {
+			case strings.Contains(name, "experimental"):
+				return BranchExperimental, true
+			case strings.Contains(name, "stable"), strings.Contains(name, "public"):
+				return BranchStable, true
+			}
+		}
+	}
+	return "", false
+}
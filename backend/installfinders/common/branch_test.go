@@ -0,0 +1,160 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/satisfactorymodding/ficsit-cli/cli/disk"
+)
+
+func TestGetSteamBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		appState map[string]interface{}
+		want     GameBranch
+		wantErr  bool
+	}{
+		{
+			name:     "no config",
+			appState: map[string]interface{}{},
+			want:     BranchStable,
+		},
+		{
+			name:     "public",
+			appState: map[string]interface{}{"UserConfig": map[string]interface{}{"BetaKey": "public"}},
+			want:     BranchStable,
+		},
+		{
+			name:     "experimental",
+			appState: map[string]interface{}{"UserConfig": map[string]interface{}{"BetaKey": "experimental"}},
+			want:     BranchExperimental,
+		},
+		{
+			name:     "key case",
+			appState: map[string]interface{}{"UserConfig": map[string]interface{}{"betakey": "Experimental"}},
+			want:     BranchExperimental,
+		},
+		{
+			name:     "mounted config",
+			appState: map[string]interface{}{"MountedConfig": map[string]interface{}{"BetaKey": "experimental"}},
+			want:     BranchExperimental,
+		},
+		{
+			name: "user config first",
+			appState: map[string]interface{}{
+				"UserConfig":    map[string]interface{}{"BetaKey": "public"},
+				"MountedConfig": map[string]interface{}{"BetaKey": "experimental"},
+			},
+			want: BranchStable,
+		},
+		{
+			name:     "unknown beta key",
+			appState: map[string]interface{}{"UserConfig": map[string]interface{}{"BetaKey": "playtest"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSteamBranch(tt.appState)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSteamBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetSteamBranch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectBranch(t *testing.T) {
+	versionFile := VersionFilePaths("")[0]
+	steamManifest := func(betaKey string) string {
+		return `"AppState"
+{
+	"appid"		"1690800"
+	"UserConfig"
+	{
+		"BetaKey"		"` + betaKey + `"
+	}
+}`
+	}
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    GameBranch
+		wantErr bool
+	}{
+		{
+			name:  "steam manifest",
+			files: map[string]string{"steamapps/appmanifest_1690800.acf": steamManifest("experimental")},
+			want:  BranchExperimental,
+		},
+		{
+			name:  "epic manifest",
+			files: map[string]string{".egstore/install.mancpn": `{"AppName": "CrabTest"}`},
+			want:  BranchExperimental,
+		},
+		{
+			name: "steam manifest before epic manifest",
+			files: map[string]string{
+				"steamapps/appmanifest_1690800.acf": steamManifest("public"),
+				".egstore/install.mancpn":           `{"AppName": "CrabTest"}`,
+			},
+			want: BranchStable,
+		},
+		{
+			name: "unknown beta key falls back to the version file",
+			files: map[string]string{
+				"steamapps/appmanifest_1690800.acf": steamManifest("playtest"),
+				versionFile:                         `{"BranchName": "++FactoryGame+rel-main-1.0.0-Experimental"}`,
+			},
+			want: BranchExperimental,
+		},
+		{
+			name:  "version file build ID",
+			files: map[string]string{versionFile: `{"BuildId": "stable-12345"}`},
+			want:  BranchStable,
+		},
+		{
+			name:    "version file without branch",
+			files:   map[string]string{versionFile: `{"BranchName": "++FactoryGame+rel-main-1.0.0"}`},
+			wantErr: true,
+		},
+		{
+			name:    "no files",
+			files:   map[string]string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installPath := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(installPath, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			d, err := disk.FromPath(installPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := DetectBranch(d, installPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectBranch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
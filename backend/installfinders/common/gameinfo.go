@@ -95,6 +95,26 @@ func GetGameInfo(path string, platform Platform) (InstallType, int, string, erro
 	return InstallTypeWindowsClient, 0, "", fmt.Errorf("failed to get game info")
 }
 
+// FindGameExecutable returns the path of the game executable of the install at path, or an empty string if there is none
+func FindGameExecutable(path string) string {
+	for _, info := range gameInfo {
+		executablePath := filepath.Join(path, info.executable)
+		if _, err := os.Stat(executablePath); err == nil {
+			return executablePath
+		}
+	}
+	return ""
+}
+
+// VersionFilePaths returns the paths where the version file of the game installed at path can be, for every install type
+func VersionFilePaths(path string) []string {
+	paths := make([]string, 0, len(gameInfo))
+	for _, info := range gameInfo {
+		paths = append(paths, filepath.Join(path, info.versionPath))
+	}
+	return paths
+}
+
 func getGameSavedDir(gamePath string, install InstallType, platform Platform) string {
 	if install == InstallTypeWindowsClient {
 		cacheDir, err := platform.CacheDir()
@@ -1,10 +1,15 @@
 package common
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 func OsPathEqual(path1, path2 string) bool {
@@ -25,15 +30,55 @@ func realPath(path string) string {
 	return newPath
 }
 
-func FindAll(finders ...InstallFinderFunc) ([]*Installation, []error) {
+type finderResult struct {
+	installs []*Installation
+	errors   []error
+}
+
+// FindAll runs the finders concurrently, and merges their results in the order of the finder names,
+// followed by the fallback finders, so the install kept for a path found by multiple finders does not depend on which finished first.
+// Finders still running after slowThreshold are logged as slow. Each finder gets its own timeout,
+// after which its context is cancelled and its results are discarded, so a broken launcher cannot block startup.
+// The errors are wrapped in InstallFinderError, so it is known which finder failed
+func FindAll(ctx context.Context, finders map[string]InstallFinderFunc, fallbackFinders map[string]InstallFinderFunc, slowThreshold time.Duration, timeout time.Duration) ([]*Installation, []error) {
+	names := make([]string, 0, len(finders))
+	for name := range finders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fallbackNames := make([]string, 0, len(fallbackFinders))
+	for name := range fallbackFinders {
+		fallbackNames = append(fallbackNames, name)
+	}
+	sort.Strings(fallbackNames)
+
+	allFinders := make([]InstallFinderFunc, 0, len(names)+len(fallbackNames))
+	for _, name := range names {
+		allFinders = append(allFinders, finders[name])
+	}
+	for _, name := range fallbackNames {
+		allFinders = append(allFinders, fallbackFinders[name])
+	}
+	names = append(names, fallbackNames...)
+
+	results := make([]finderResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runFinder(ctx, name, allFinders[i], slowThreshold, timeout)
+		}()
+	}
+	wg.Wait()
+
 	installs := make([]*Installation, 0)
 	var errors []error
-	for _, finder := range finders {
-		foundInstalls, foundErrors := finder()
-		for _, install := range foundInstalls {
+	for i, name := range names {
+		for _, install := range results[i].installs {
 			existing := false
-			for i := range installs {
-				if OsPathEqual(installs[i].Path, install.Path) {
+			for j := range installs {
+				if OsPathEqual(installs[j].Path, install.Path) {
 					existing = true
 					break
 				}
@@ -42,7 +87,62 @@ func FindAll(finders ...InstallFinderFunc) ([]*Installation, []error) {
 				installs = append(installs, install)
 			}
 		}
-		errors = append(errors, foundErrors...)
+		for _, err := range results[i].errors {
+			errors = append(errors, InstallFinderError{Finder: name, Inner: err})
+		}
 	}
 	return installs, errors
 }
+
+// runFinder runs the finder until it returns or its timeout expires, logging a warning if it is still running after slowThreshold.
+// The finder's context is cancelled on timeout, but finders that ignore it are abandoned rather than waited for
+func runFinder(ctx context.Context, name string, finder InstallFinderFunc, slowThreshold time.Duration, timeout time.Duration) finderResult {
+	start := time.Now()
+
+	finderCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan finderResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- finderResult{errors: []error{fmt.Errorf("finder panicked: %v", r)}}
+			}
+		}()
+		installs, errs := finder(finderCtx)
+		done <- finderResult{installs: installs, errors: errs}
+	}()
+
+	slowTimer := time.NewTimer(slowThreshold)
+	defer slowTimer.Stop()
+
+	var result finderResult
+	select {
+	case result = <-done:
+	case <-slowTimer.C:
+		slog.Warn("install finder is slow", slog.String("finder", name), slog.Duration("after", slowThreshold), slog.Duration("timeout", timeout))
+		select {
+		case result = <-done:
+		case <-finderCtx.Done():
+			result = finderStopped(ctx, timeout)
+		}
+	case <-finderCtx.Done():
+		result = finderStopped(ctx, timeout)
+	}
+
+	slog.Info("install finder finished",
+		slog.String("finder", name),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int("installs", len(result.installs)),
+		slog.Int("errors", len(result.errors)),
+	)
+	return result
+}
+
+// finderStopped is the result of a finder whose context ended before it returned
+func finderStopped(ctx context.Context, timeout time.Duration) finderResult {
+	if ctx.Err() != nil {
+		return finderResult{errors: []error{fmt.Errorf("finder cancelled: %w", ctx.Err())}}
+	}
+	return finderResult{errors: []error{fmt.Errorf("timed out after %s", timeout)}}
+}
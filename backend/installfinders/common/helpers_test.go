@@ -0,0 +1,169 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func finderReturning(delay time.Duration, paths ...string) InstallFinderFunc {
+	return func(ctx context.Context) ([]*Installation, []error) {
+		time.Sleep(delay)
+		installs := make([]*Installation, 0, len(paths))
+		for _, path := range paths {
+			installs = append(installs, &Installation{Path: path, Launcher: path})
+		}
+		return installs, nil
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name            string
+		finders         map[string]InstallFinderFunc
+		fallbackFinders map[string]InstallFinderFunc
+		wantPaths       []string
+		wantLaunchers   []string
+		wantErrors      []string
+	}{
+		{
+			name: "results are ordered by finder name",
+			finders: map[string]InstallFinderFunc{
+				"b": finderReturning(0, "/b"),
+				"a": finderReturning(10*time.Millisecond, "/a"),
+			},
+			wantPaths: []string{"/a", "/b"},
+		},
+		{
+			name: "fallback finders come last",
+			finders: map[string]InstallFinderFunc{
+				"z": finderReturning(0, "/z"),
+			},
+			fallbackFinders: map[string]InstallFinderFunc{
+				"a": finderReturning(0, "/a"),
+			},
+			wantPaths: []string{"/z", "/a"},
+		},
+		{
+			name: "the first finder by name keeps a path found by multiple finders",
+			finders: map[string]InstallFinderFunc{
+				"b": func(context.Context) ([]*Installation, []error) {
+					return []*Installation{{Path: "/game", Launcher: "b"}}, nil
+				},
+				"a": func(context.Context) ([]*Installation, []error) {
+					time.Sleep(10 * time.Millisecond)
+					return []*Installation{{Path: "/game", Launcher: "a"}}, nil
+				},
+			},
+			wantPaths:     []string{"/game"},
+			wantLaunchers: []string{"a"},
+		},
+		{
+			name: "slow finders within the timeout are waited for",
+			finders: map[string]InstallFinderFunc{
+				"slow": finderReturning(50*time.Millisecond, "/slow"),
+			},
+			wantPaths: []string{"/slow"},
+		},
+		{
+			name: "errors are attributed to their finder",
+			finders: map[string]InstallFinderFunc{
+				"broken": func(context.Context) ([]*Installation, []error) {
+					return nil, []error{errors.New("failed")}
+				},
+				"panicking": func(context.Context) ([]*Installation, []error) {
+					panic("oops")
+				},
+			},
+			wantPaths:  []string{},
+			wantErrors: []string{"broken", "panicking"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installs, errs := FindAll(context.Background(), tt.finders, tt.fallbackFinders, 10*time.Millisecond, time.Second)
+
+			if len(installs) != len(tt.wantPaths) {
+				t.Fatalf("got %d installs, want %d", len(installs), len(tt.wantPaths))
+			}
+			for i, install := range installs {
+				if install.Path != tt.wantPaths[i] {
+					t.Errorf("install %d path = %s, want %s", i, install.Path, tt.wantPaths[i])
+				}
+				if tt.wantLaunchers != nil && install.Launcher != tt.wantLaunchers[i] {
+					t.Errorf("install %d launcher = %s, want %s", i, install.Launcher, tt.wantLaunchers[i])
+				}
+			}
+
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("got %d errors, want %d: %v", len(errs), len(tt.wantErrors), errs)
+			}
+			for i, err := range errs {
+				var finderErr InstallFinderError
+				if !errors.As(err, &finderErr) {
+					t.Fatalf("error %d is not an InstallFinderError: %v", i, err)
+				}
+				if finderErr.Finder != tt.wantErrors[i] {
+					t.Errorf("error %d finder = %s, want %s", i, finderErr.Finder, tt.wantErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	finders := map[string]InstallFinderFunc{
+		"blocked": func(ctx context.Context) ([]*Installation, []error) {
+			time.Sleep(time.Second)
+			return []*Installation{{Path: "/late"}}, nil
+		},
+	}
+
+	installs, errs := FindAll(ctx, finders, nil, time.Millisecond, time.Minute)
+	if len(installs) != 0 {
+		t.Errorf("got %d installs from a cancelled search, want 0", len(installs))
+	}
+	if len(errs) != 1 {
+		t.Errorf("got %d errors, want 1", len(errs))
+	}
+}
+
+func TestFindAllTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	finders := map[string]InstallFinderFunc{
+		"blocking": func(context.Context) ([]*Installation, []error) {
+			// Ignores its context, like a finder stuck in a syscall
+			<-unblock
+			return []*Installation{{Path: "/late"}}, nil
+		},
+		"fast": finderReturning(0, "/fast"),
+	}
+
+	start := time.Now()
+	installs, errs := FindAll(context.Background(), finders, nil, time.Millisecond, 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FindAll took %s, want it to return after the timeout", elapsed)
+	}
+
+	if len(installs) != 1 || installs[0].Path != "/fast" {
+		t.Errorf("installs = %v, want only /fast", installs)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+	}
+	var finderErr InstallFinderError
+	if !errors.As(errs[0], &finderErr) || finderErr.Finder != "blocking" {
+		t.Fatalf("error = %v, want an InstallFinderError of the blocking finder", errs[0])
+	}
+	if !strings.Contains(errs[0].Error(), "timed out after 50ms") {
+		t.Errorf("error = %v, want it to say the finder timed out", errs[0])
+	}
+}
@@ -1,5 +1,7 @@
 package common
 
+import "context"
+
 type GameBranch string
 
 var (
@@ -31,6 +33,8 @@ type Installation struct {
 	Launcher   string       `json:"launcher"`
 	LaunchPath []string     `json:"launchPath"`
 	SavedPath  string       `json:"-"`
+	// ProtonVersion is the Proton or compatibility tool the game runs with, for Steam installs on Linux
+	ProtonVersion string `json:"protonVersion,omitempty"`
 }
 
 type InstallFindError struct {
@@ -46,7 +50,22 @@ func (e InstallFindError) Cause() error {
 	return e.Inner
 }
 
-type InstallFinderFunc func() ([]*Installation, []error)
+// InstallFinderError is an error of a specific install finder
+type InstallFinderError struct {
+	Finder string `json:"finder"`
+	Inner  error  `json:"cause"`
+}
+
+func (e InstallFinderError) Error() string {
+	return e.Finder + ": " + e.Inner.Error()
+}
+
+func (e InstallFinderError) Unwrap() error {
+	return e.Inner
+}
+
+// InstallFinderFunc finds the installs of a launcher. It should stop when the context is cancelled
+type InstallFinderFunc func(ctx context.Context) ([]*Installation, []error)
 
 var AllInstallTypes = []struct {
 	Value  InstallType
@@ -1,8 +1,10 @@
 package installfinders
 
 import (
+	"context"
 	"log/slog"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/maps"
 
@@ -12,10 +14,21 @@ import (
 	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/all" // register all launchers
 )
 
+const (
+	// Finders that take longer are logged as slow, since they delay startup
+	slowFinderThreshold = 5 * time.Second
+	// Finders that take longer are abandoned, so a hanging launcher CLI or an unresponsive network mount does not block startup
+	finderTimeout = 30 * time.Second
+)
+
 func FindInstallations() ([]*common.Installation, []error) {
 	registrations := launchers.GetInstallFinders()
+	fallbackRegistrations := launchers.GetFallbackInstallFinders()
 
-	slog.Debug("finding installations", slog.String("launchers", strings.Join(maps.Keys(registrations), ",")))
+	slog.Debug("finding installations",
+		slog.String("launchers", strings.Join(maps.Keys(registrations), ",")),
+		slog.String("fallbackLaunchers", strings.Join(maps.Keys(fallbackRegistrations), ",")),
+	)
 
-	return common.FindAll(maps.Values(registrations)...)
+	return common.FindAll(context.Background(), registrations, fallbackRegistrations, slowFinderThreshold, finderTimeout)
 }
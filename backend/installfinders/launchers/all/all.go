@@ -1,12 +1,15 @@
 package all
 
 import (
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/crossover" // register crossover
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/custom"    // register custom
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/epic"      // register epic
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/heroic"    // register heroic
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/legendary" // register legendary
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/lutris"    // register lutris
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/steam"     // register steam
-	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/whisky"    // register whisky
-)
\ No newline at end of file
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/bottles"      // register bottles
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/crossover"    // register crossover
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/custom"       // register custom
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/epic"         // register epic
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/heroic"       // register heroic
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/legendary"    // register legendary
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/localservers" // register local servers
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/lutris"       // register lutris
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/searchroots"  // register search roots
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/steam"        // register steam
+	_ "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/whisky"       // register whisky
+)
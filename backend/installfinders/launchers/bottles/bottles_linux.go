@@ -0,0 +1,120 @@
+package bottles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/epic"
+)
+
+type bottleConfig struct {
+	Name string `yaml:"Name"`
+	// Path is the directory name of the bottle, or its full path if CustomPath is set
+	Path       string `yaml:"Path"`
+	CustomPath bool   `yaml:"Custom_Path"`
+}
+
+var (
+	epicLauncherPath     = `C:\Program Files (x86)\Epic Games\Launcher\Portal\Binaries\Win32\EpicGamesLauncher.exe`
+	bottlesRelativePath  = filepath.Join("bottles", "bottles")
+	flatpakDataDirectory = filepath.Join(".var", "app", "com.usebottles.bottles", "data")
+)
+
+func init() {
+	launchers.Add("Bottles", func(_ context.Context) ([]*common.Installation, []error) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, []error{fmt.Errorf("failed to get user home dir: %w", err)}
+		}
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+		return findInstallations(filepath.Join(dataHome, bottlesRelativePath), []string{"bottles-cli"})
+	})
+	launchers.Add("Bottles-flatpak", func(_ context.Context) ([]*common.Installation, []error) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, []error{fmt.Errorf("failed to get user home dir: %w", err)}
+		}
+		return findInstallations(
+			filepath.Join(homeDir, flatpakDataDirectory, bottlesRelativePath),
+			[]string{"flatpak", "run", "--command=bottles-cli", "com.usebottles.bottles"},
+		)
+	})
+}
+
+func findInstallations(bottlesPath string, bottlesCliCmd []string) ([]*common.Installation, []error) {
+	if _, err := os.Stat(bottlesPath); os.IsNotExist(err) {
+		return nil, []error{fmt.Errorf("bottles not installed")}
+	}
+
+	bottles, err := os.ReadDir(bottlesPath)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list bottles: %w", err)}
+	}
+
+	installs := make([]*common.Installation, 0)
+	var findErrors []error
+	for _, bottle := range bottles {
+		if !bottle.IsDir() {
+			continue
+		}
+
+		config, err := readBottleConfig(filepath.Join(bottlesPath, bottle.Name(), "bottle.yml"))
+		if err != nil {
+			findErrors = append(findErrors, err)
+			continue
+		}
+
+		bottleRoot := filepath.Join(bottlesPath, bottle.Name())
+		if config.CustomPath && filepath.IsAbs(config.Path) {
+			bottleRoot = config.Path
+		}
+
+		bottleInstalls, bottleErrors := epic.FindInstallationsWineWithLaunchCommand(
+			bottleRoot,
+			"Bottles - "+config.Name,
+			func(appName string) []string {
+				return append(
+					append([]string{}, bottlesCliCmd...),
+					"run",
+					"-b", config.Name,
+					"-e", epicLauncherPath,
+					"-a", `com.epicgames.launcher://apps/`+appName+`?action=launch&silent=true`,
+				)
+			},
+		)
+		// Bottles without Epic are not an error
+		bottleErrors = slices.DeleteFunc(bottleErrors, func(err error) bool {
+			return errors.Is(err, epic.ErrNotInstalledWine)
+		})
+		installs = append(installs, bottleInstalls...)
+		findErrors = append(findErrors, bottleErrors...)
+	}
+
+	return installs, findErrors
+}
+
+func readBottleConfig(path string) (*bottleConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bottle config %s: %w", path, err)
+	}
+	var config bottleConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse bottle config %s: %w", path, err)
+	}
+	if config.Name == "" {
+		config.Name = filepath.Base(filepath.Dir(path))
+	}
+	return &config, nil
+}
@@ -1,6 +1,7 @@
 package crossover
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -22,7 +23,7 @@ func init() {
 	launchers.Add("CrossOver", crossover)
 }
 
-func crossover() ([]*common.Installation, []error) {
+func crossover(_ context.Context) ([]*common.Installation, []error) {
 	bottlesPath, err := getCrossoverBottlesPath()
 	if err != nil {
 		return nil, []error{fmt.Errorf("failed to get CrossOver bottles path: %w", err)}
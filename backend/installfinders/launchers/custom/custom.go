@@ -1,6 +1,7 @@
 package custom
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -20,7 +21,7 @@ func init() {
 	launchers.Add("custom", FindInstallationsCustom)
 }
 
-func FindInstallationsCustom() ([]*common.Installation, []error) {
+func FindInstallationsCustom(_ context.Context) ([]*common.Installation, []error) {
 	// This finder doesn't automatically find installations
 	// It's used for manually added custom installations
 	return nil, nil
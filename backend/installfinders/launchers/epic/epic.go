@@ -25,26 +25,9 @@ type GameManifest struct {
 	CatalogItemID    string `json:"CatalogItemID"`
 }
 
-var (
-	EarlyAccessAppName                 = "CrabEA"
-	ExperimentalAppName                = "CrabTest"
-	EarlyAccessDedicatedServerAppName  = "CrabDedicatedServer"
-	ExperimentalDedicatedServerAppName = "c509233193024c5f8124467d3aa36199"
-)
-
+// GetEpicBranch returns the branch of an Epic app name
 func GetEpicBranch(appName string) (common.GameBranch, error) {
-	switch appName {
-	case EarlyAccessAppName:
-		return common.BranchStable, nil
-	case ExperimentalAppName:
-		return common.BranchExperimental, nil
-	case EarlyAccessDedicatedServerAppName:
-		return common.BranchStable, nil
-	case ExperimentalDedicatedServerAppName:
-		return common.BranchExperimental, nil
-	default:
-		return "", fmt.Errorf("unknown branch for %s", appName)
-	}
+	return common.GetEpicBranch(appName) //nolint:wrapcheck
 }
 
 func FindInstallationsEpic(epicManifestsPath string, launcher string, platform common.LauncherPlatform) ([]*common.Installation, []error) {
@@ -136,11 +119,8 @@ func FindInstallationsEpic(epicManifestsPath string, launcher string, platform c
 
 		branch, err := GetEpicBranch(epicManifest.MainGameAppName)
 		if err != nil {
-			findErrors = append(findErrors, common.InstallFindError{
-				Path:  installLocation,
-				Inner: err,
-			})
-			continue
+			// Unknown app names are usually new servers or branches, the install itself may still tell
+			branch = common.DetectLocalBranch(installLocation)
 		}
 
 		installs = append(installs, &common.Installation{
@@ -1,6 +1,7 @@
 package epic
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -13,7 +14,7 @@ import (
 var epicProgramDataManifestsFolder = filepath.Join("Epic", "EpicGamesLauncher", "Data", "Manifests")
 
 func init() {
-	launchers.Add("EpicGames", func() ([]*common.Installation, []error) {
+	launchers.Add("EpicGames", func(_ context.Context) ([]*common.Installation, []error) {
 		programData, err := windows.KnownFolderPath(windows.FOLDERID_ProgramData, 0)
 		if err != nil {
 			return nil, []error{fmt.Errorf("failed to get ProgramData folder: %w", err)}
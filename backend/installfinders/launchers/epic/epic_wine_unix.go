@@ -3,6 +3,7 @@
 package epic
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,16 +13,25 @@ import (
 
 var epicWineManifestPath = filepath.Join("c:", "ProgramData", "Epic", "EpicGamesLauncher", "Data", "Manifests")
 
+// ErrNotInstalledWine is returned when the wine prefix has no Epic Games Launcher
+var ErrNotInstalledWine = errors.New("Epic is not installed")
+
 func FindInstallationsWine(winePrefix string, launcher string, launchPath []string) ([]*common.Installation, []error) {
+	return FindInstallationsWineWithLaunchCommand(winePrefix, launcher, func(_ string) []string { return launchPath })
+}
+
+// FindInstallationsWineWithLaunchCommand finds the Epic installs of the wine prefix,
+// for launchers whose launch command depends on the Epic app name
+func FindInstallationsWineWithLaunchCommand(winePrefix string, launcher string, launchPath func(appName string) []string) ([]*common.Installation, []error) {
 	platform := common.WineLauncherPlatform(winePrefix)
 
 	if _, err := os.Stat(platform.ProcessPath(epicWineManifestPath)); os.IsNotExist(err) {
-		return nil, []error{fmt.Errorf("Epic is not installed in %s", winePrefix)}
+		return nil, []error{fmt.Errorf("%w in %s", ErrNotInstalledWine, winePrefix)}
 	}
 
 	return FindInstallationsEpic(
 		epicWineManifestPath,
 		launcher,
-		common.MakeLauncherPlatform(platform, func(_ string) []string { return launchPath }),
+		common.MakeLauncherPlatform(platform, launchPath),
 	)
 }
@@ -1,12 +1,14 @@
 package heroic
 
 import (
+	"context"
+
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers"
 )
 
 func init() {
-	launchers.Add("Heroic", func() ([]*common.Installation, []error) {
+	launchers.Add("Heroic", func(_ context.Context) ([]*common.Installation, []error) {
 		return findInstallationsHeroic(false, "", "Heroic")
 	})
 }
@@ -1,6 +1,7 @@
 package heroic
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,7 +12,7 @@ import (
 )
 
 func init() {
-	launchers.Add("Heroic-snap", func() ([]*common.Installation, []error) {
+	launchers.Add("Heroic-snap", func(_ context.Context) ([]*common.Installation, []error) {
 		snapPath, err := getSnapPath()
 		if err != nil {
 			return nil, []error{fmt.Errorf("failed to get snap path: %w", err)}
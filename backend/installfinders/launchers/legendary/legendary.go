@@ -81,11 +81,8 @@ func FindInstallationsIn(legendaryDataPath string, launcher string, knownPrefixe
 
 		branch, err := epic.GetEpicBranch(legendaryGame.AppName)
 		if err != nil {
-			findErrors = append(findErrors, common.InstallFindError{
-				Path:  installLocation,
-				Inner: err,
-			})
-			continue
+			// Unknown app names are usually new servers or branches, the install itself may still tell
+			branch = common.DetectLocalBranch(installLocation)
 		}
 
 		installs = append(installs, &common.Installation{
@@ -1,6 +1,7 @@
 package legendary
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 
@@ -9,7 +10,7 @@ import (
 )
 
 func init() {
-	launchers.Add("Legendary", func() ([]*common.Installation, []error) {
+	launchers.Add("Legendary", func(_ context.Context) ([]*common.Installation, []error) {
 		legendaryDataPath, err := getGlobalLegendaryDataPath("")
 		if err != nil {
 			return nil, []error{fmt.Errorf("failed to get legendary config path: %w", err)}
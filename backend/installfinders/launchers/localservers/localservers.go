@@ -0,0 +1,81 @@
+package localservers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+const LauncherName = "Local server"
+
+func init() {
+	launchers.Add("LocalServers", findInstallations)
+}
+
+// findInstallations validates the local servers added by the user, the same way they were validated when added
+func findInstallations(_ context.Context) ([]*common.Installation, []error) {
+	installs := make([]*common.Installation, 0)
+	var findErrors []error
+	for _, path := range settings.Settings.LocalServers {
+		install, err := GetInstallation(path)
+		if err != nil {
+			findErrors = append(findErrors, common.InstallFindError{
+				Path:  path,
+				Inner: err,
+			})
+			continue
+		}
+		installs = append(installs, install)
+	}
+	return installs, findErrors
+}
+
+// GetInstallation validates that path is a dedicated server install, and returns its info
+func GetInstallation(path string) (*common.Installation, error) {
+	if !filepath.IsAbs(path) {
+		return nil, fmt.Errorf("path must be absolute")
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access directory: %w", err)
+	}
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("path is not a directory")
+	}
+
+	executable := common.FindGameExecutable(path)
+	if executable == "" {
+		return nil, fmt.Errorf("no FactoryServer.sh or FactoryServer.exe found in the directory")
+	}
+
+	installType, version, savedPath, err := common.GetGameInfo(path, common.NativePlatform())
+	if err != nil {
+		return nil, fmt.Errorf("the game version file is missing or unreadable: %w", err)
+	}
+	if installType == common.InstallTypeWindowsClient {
+		return nil, fmt.Errorf("the directory contains the game client, not a dedicated server")
+	}
+
+	var launchPath []string
+	if (installType == common.InstallTypeLinuxServer && runtime.GOOS == "linux") || (installType == common.InstallTypeWindowsServer && runtime.GOOS == "windows") {
+		launchPath = []string{executable}
+	}
+
+	return &common.Installation{
+		Path:       filepath.Clean(path),
+		Version:    version,
+		Type:       installType,
+		Location:   common.LocationTypeLocal,
+		Branch:     common.DetectLocalBranch(path),
+		Launcher:   LauncherName,
+		LaunchPath: launchPath,
+		SavedPath:  savedPath,
+	}, nil
+}
@@ -1,12 +1,20 @@
 package lutris
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
+	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite" // register the sqlite driver
+
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers"
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers/epic"
@@ -18,20 +26,133 @@ type Game struct {
 	Name      string `json:"name"`
 	Runner    string `json:"runner"`
 	Directory string `json:"directory"`
+	// Prefix is the wine prefix from the game's config, only known when reading the database
+	Prefix string `json:"-"`
+}
+
+// gameConfig is the part of a Lutris game YAML config needed to find the wine prefix
+type gameConfig struct {
+	Game struct {
+		Prefix string `yaml:"prefix"`
+	} `yaml:"game"`
 }
 
 func init() {
-	launchers.Add("Lutris", func() ([]*common.Installation, []error) {
-		return findInstallations([]string{"lutris"}, "Lutris")
+	launchers.Add("Lutris", func(ctx context.Context) ([]*common.Installation, []error) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, []error{fmt.Errorf("failed to get user home dir: %w", err)}
+		}
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(homeDir, ".config")
+		}
+		return findInstallations(ctx, []string{"lutris"}, filepath.Join(dataHome, "lutris"), filepath.Join(configHome, "lutris"), "Lutris")
 	})
-	launchers.Add("Lutris-flatpak", func() ([]*common.Installation, []error) {
-		return findInstallations([]string{"flatpak", "run", "net.lutris.Lutris"}, "Lutris")
+	launchers.Add("Lutris-flatpak", func(ctx context.Context) ([]*common.Installation, []error) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, []error{fmt.Errorf("failed to get user home dir: %w", err)}
+		}
+		flatpakHome := filepath.Join(homeDir, ".var", "app", "net.lutris.Lutris")
+		return findInstallations(ctx, []string{"flatpak", "run", "net.lutris.Lutris"}, filepath.Join(flatpakHome, "data", "lutris"), filepath.Join(flatpakHome, "config", "lutris"), "Lutris")
 	})
 }
 
-func findInstallations(lutrisCmd []string, launcher string) ([]*common.Installation, []error) {
+// findInstallations reads the Lutris games from its database, and only runs the Lutris CLI if there is no database,
+// since the CLI is slow and does not work in every environment
+func findInstallations(ctx context.Context, lutrisCmd []string, dataDir string, configDir string, launcher string) ([]*common.Installation, []error) {
+	var lutrisGames []Game
+	var err error
+	databasePath := filepath.Join(dataDir, "pga.db")
+	if _, statErr := os.Stat(databasePath); statErr == nil {
+		lutrisGames, err = getGamesFromDatabase(ctx, databasePath, []string{filepath.Join(dataDir, "games"), filepath.Join(configDir, "games")})
+	} else {
+		lutrisGames, err = getGamesFromCLI(ctx, lutrisCmd)
+	}
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	installs := []*common.Installation{}
+	findErrors := []error{}
+	for _, lutrisGame := range lutrisGames {
+		winePrefix := lutrisGame.Prefix
+		if winePrefix == "" {
+			winePrefix = lutrisGame.Directory
+		}
+		currentInstalls, errs := epic.FindInstallationsWine(winePrefix, launcher+" - "+lutrisGame.Name, makeLutrisCmd(lutrisCmd, "lutris:rungame/"+lutrisGame.Slug))
+		installs = append(installs, currentInstalls...)
+		if errs != nil {
+			findErrors = append(findErrors, errs...)
+		}
+	}
+	return installs, findErrors
+}
+
+func getGamesFromDatabase(ctx context.Context, databasePath string, gameConfigDirs []string) ([]Game, error) {
+	// The path is escaped, since ? and # in it would otherwise start the query or fragment of the URI
+	dsn := url.URL{Scheme: "file", Path: databasePath, RawQuery: "mode=ro"}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lutris database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `SELECT id, slug, name, runner, directory, configpath FROM games`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lutris database: %w", err)
+	}
+	defer rows.Close()
+
+	var lutrisGames []Game
+	for rows.Next() {
+		var game Game
+		var slug, name, runner, directory, configPath sql.NullString
+		if err := rows.Scan(&game.ID, &slug, &name, &runner, &directory, &configPath); err != nil {
+			return nil, fmt.Errorf("failed to read lutris game: %w", err)
+		}
+		game.Slug = slug.String
+		game.Name = name.String
+		game.Runner = runner.String
+		game.Directory = directory.String
+		if configPath.String != "" {
+			game.Prefix = readGamePrefix(gameConfigDirs, configPath.String)
+		}
+		lutrisGames = append(lutrisGames, game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lutris games: %w", err)
+	}
+
+	return lutrisGames, nil
+}
+
+// readGamePrefix returns the wine prefix from the game's config, or an empty string if it is not set.
+// Newer Lutris versions keep the configs in the data directory, older ones in the config directory
+func readGamePrefix(gameConfigDirs []string, configPath string) string {
+	for _, dir := range gameConfigDirs {
+		data, err := os.ReadFile(filepath.Join(dir, configPath+".yml"))
+		if err != nil {
+			continue
+		}
+		var config gameConfig
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			slog.Warn("failed to parse lutris game config", slog.String("path", filepath.Join(dir, configPath+".yml")), slog.Any("error", err))
+			continue
+		}
+		return config.Game.Prefix
+	}
+	return ""
+}
+
+func getGamesFromCLI(ctx context.Context, lutrisCmd []string) ([]Game, error) {
 	lutrisLjCmd := makeLutrisCmd(lutrisCmd, "-lj")
-	lutrisLj := exec.Command(lutrisLjCmd[0], lutrisLjCmd[1:]...)
+	lutrisLj := exec.CommandContext(ctx, lutrisLjCmd[0], lutrisLjCmd[1:]...)
 	lutrisLj.Env = os.Environ()
 	lutrisLj.Env = append(lutrisLj.Env, "LUTRIS_SKIP_INIT=1")
 	if os.Getenv("APPIMAGE") != "" {
@@ -51,28 +172,14 @@ func findInstallations(lutrisCmd []string, launcher string) ([]*common.Installat
 	}
 	outputBytes, err := lutrisLj.Output()
 	if err != nil {
-		return nil, []error{
-			fmt.Errorf("failed to run lutris -lj: %w", err),
-		}
+		return nil, fmt.Errorf("failed to run lutris -lj: %w", err)
 	}
 	var lutrisGames []Game
 	err = json.Unmarshal(outputBytes, &lutrisGames)
 	if err != nil {
-		return nil, []error{
-			fmt.Errorf("failed to parse lutris -lj output: %w", err),
-		}
+		return nil, fmt.Errorf("failed to parse lutris -lj output: %w", err)
 	}
-
-	installs := []*common.Installation{}
-	findErrors := []error{}
-	for _, lutrisGame := range lutrisGames {
-		currentInstalls, errs := epic.FindInstallationsWine(lutrisGame.Directory, launcher+" - "+lutrisGame.Name, makeLutrisCmd(lutrisCmd, "lutris:rungame/"+lutrisGame.Slug))
-		installs = append(installs, currentInstalls...)
-		if errs != nil {
-			findErrors = append(findErrors, errs...)
-		}
-	}
-	return installs, findErrors
+	return lutrisGames, nil
 }
 
 func makeLutrisCmd(lutrisCmd []string, args ...string) []string {
@@ -2,7 +2,10 @@ package launchers
 
 import "github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
 
-var finders map[string]common.InstallFinderFunc
+var (
+	finders         map[string]common.InstallFinderFunc
+	fallbackFinders map[string]common.InstallFinderFunc
+)
 
 func Add(id string, f common.InstallFinderFunc) {
 	if finders == nil {
@@ -14,6 +17,22 @@ func Add(id string, f common.InstallFinderFunc) {
 	finders[id] = f
 }
 
+// AddFallback registers a finder whose installs are only used if no regular finder found them,
+// for finders that have less information about the installs than the launchers do
+func AddFallback(id string, f common.InstallFinderFunc) {
+	if fallbackFinders == nil {
+		fallbackFinders = make(map[string]common.InstallFinderFunc)
+	}
+	if _, ok := fallbackFinders[id]; ok {
+		panic("launcher already registered")
+	}
+	fallbackFinders[id] = f
+}
+
+func GetFallbackInstallFinders() map[string]common.InstallFinderFunc {
+	return fallbackFinders
+}
+
 func GetInstallFinders() map[string]common.InstallFinderFunc {
 	return finders
 }
@@ -0,0 +1,112 @@
+package searchroots
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+const LauncherName = "Search root"
+
+// maxSearchDepth is how many directories below a root installs are searched for,
+// enough for <library>/steamapps/common/<game>
+const maxSearchDepth = 4
+
+func init() {
+	// Installs in the search roots may also be found by their launcher, which knows more about them
+	launchers.AddFallback("SearchRoots", findInstallations)
+}
+
+func findInstallations(ctx context.Context) ([]*common.Installation, []error) {
+	installs := make([]*common.Installation, 0)
+	var findErrors []error
+
+	for _, root := range settings.Settings.ExtraSearchRoots {
+		rootInstalls, rootErrors := searchRoot(ctx, root)
+		installs = append(installs, rootInstalls...)
+		findErrors = append(findErrors, rootErrors...)
+	}
+
+	return installs, findErrors
+}
+
+func searchRoot(ctx context.Context, root string) ([]*common.Installation, []error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, []error{fmt.Errorf("failed to access search root %s: %w", root, err)}
+	}
+
+	installs := make([]*common.Installation, 0)
+	var findErrors []error
+
+	// Breadth first, so an unreadable deep directory does not hide the others
+	currentLevel := []string{root}
+	for depth := 0; depth <= maxSearchDepth && len(currentLevel) > 0; depth++ {
+		var nextLevel []string
+		for _, dir := range currentLevel {
+			if err := ctx.Err(); err != nil {
+				return installs, append(findErrors, fmt.Errorf("search of %s cancelled: %w", root, err))
+			}
+
+			if common.FindGameExecutable(dir) != "" {
+				install, err := getInstallation(dir)
+				if err != nil {
+					findErrors = append(findErrors, common.InstallFindError{
+						Path:  dir,
+						Inner: err,
+					})
+				} else {
+					installs = append(installs, install)
+				}
+				// Installs are not nested
+				continue
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				if dir == root {
+					findErrors = append(findErrors, fmt.Errorf("failed to read search root %s: %w", root, err))
+				}
+				continue
+			}
+			for _, entry := range entries {
+				// Symlinked directories are not followed, to avoid loops
+				if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+					nextLevel = append(nextLevel, filepath.Join(dir, entry.Name()))
+				}
+			}
+		}
+		currentLevel = nextLevel
+	}
+
+	return installs, findErrors
+}
+
+func getInstallation(path string) (*common.Installation, error) {
+	installType, version, savedPath, err := common.GetGameInfo(path, common.NativePlatform())
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	var launchPath []string
+	if installType != common.InstallTypeWindowsClient || runtime.GOOS == "windows" {
+		launchPath = []string{common.FindGameExecutable(path)}
+	}
+
+	return &common.Installation{
+		Path:       filepath.Clean(path),
+		Version:    version,
+		Type:       installType,
+		Location:   common.LocationTypeLocal,
+		Branch:     common.DetectLocalBranch(path),
+		Launcher:   LauncherName,
+		LaunchPath: launchPath,
+		SavedPath:  savedPath,
+	}, nil
+}
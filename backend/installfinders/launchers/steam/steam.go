@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/andygrunwald/vdf"
 
@@ -42,6 +43,14 @@ func FindInstallationsSteam(steamPath string, launcher string, platform common.L
 	installs := make([]*common.Installation, 0)
 	var findErrors []error
 
+	var compatToolMapping map[string]string
+	if platform.Os() != "windows" {
+		compatToolMapping, err = getCompatToolMapping(platform.ProcessPath(filepath.Join(steamPath, "config", "config.vdf")))
+		if err != nil {
+			slog.Warn("failed to read steam compatibility tools", slog.Any("error", err))
+		}
+	}
+
 	for _, libraryFolder := range libraryFolders {
 		for _, manifest := range manifests {
 			manifestPath := platform.ProcessPath(filepath.Join(libraryFolder, "steamapps", manifest))
@@ -73,18 +82,20 @@ func FindInstallationsSteam(steamPath string, launcher string, platform common.L
 			fullInstallationPath := platform.ProcessPath(filepath.Join(libraryFolder, "steamapps", "common", appState["installdir"].(string)))
 
 			gamePlatform := platform.Platform
+			var protonVersion string
 			if platform.Os() != "windows" {
 				// The game might be running under Proton
 				// There's no appmanifest field that would specify it, but if the proton prefix exists,
 				// the game is most likely running under Proton.
-				gameProtonPrefix := platform.ProcessPath(filepath.Join(steamPath, "steamapps", "compatdata", appState["appid"].(string), "pfx"))
-				_, err := os.Stat(gameProtonPrefix)
-				if err != nil && !os.IsNotExist(err) {
-					findErrors = append(findErrors, fmt.Errorf("failed to find proton prefix for game %s: %w", appState["appid"].(string), err))
+				appID := appState["appid"].(string)
+				compatDataPath, err := findCompatData(platform, []string{libraryFolder, steamPath}, appID)
+				if err != nil {
+					findErrors = append(findErrors, fmt.Errorf("failed to find proton prefix for game %s: %w", appID, err))
 					continue
 				}
-				if err == nil {
-					gamePlatform = common.WineLauncherPlatform(gameProtonPrefix)
+				if compatDataPath != "" {
+					gamePlatform = common.WineLauncherPlatform(filepath.Join(compatDataPath, "pfx"))
+					protonVersion = getProtonVersion(compatDataPath, compatToolMapping[appID])
 				}
 			}
 
@@ -97,17 +108,10 @@ func FindInstallationsSteam(steamPath string, launcher string, platform common.L
 				continue
 			}
 
-			var branch common.GameBranch
-			userConfig := manifest["AppState"].(map[string]interface{})["UserConfig"].(map[string]interface{})
-			betakey, ok := userConfig["betakey"]
-			if !ok {
-				branch = common.BranchStable
-			} else {
-				if betakey == "experimental" {
-					branch = common.BranchExperimental
-				} else {
-					findErrors = append(findErrors, fmt.Errorf("unknown beta key %s", betakey))
-				}
+			branch, err := common.GetSteamBranch(appState)
+			if err != nil {
+				// Unknown beta keys are usually new branches, the install itself may still tell
+				branch = common.DetectLocalBranch(fullInstallationPath)
 			}
 
 			installs = append(installs, &common.Installation{
@@ -119,7 +123,8 @@ func FindInstallationsSteam(steamPath string, launcher string, platform common.L
 				Launcher:   launcher,
 				LaunchPath: platform.LauncherCommand(`steam://rungameid/526870`),
 				// pass wine platform if necessary, as platform here is going to be native
-				SavedPath: savedPath,
+				SavedPath:     savedPath,
+				ProtonVersion: protonVersion,
 			})
 		}
 	}
@@ -168,3 +173,84 @@ func getLibraryFoldersFromManifest(libraryFoldersManifestPath string) ([]string,
 
 	return libraryFolders, nil
 }
+
+// findCompatData returns the Proton compatdata directory of the app, which is in the library the app is installed in,
+// or in the main Steam library for older installs. Returns an empty string if the app does not use Proton
+func findCompatData(platform common.LauncherPlatform, libraryFolders []string, appID string) (string, error) {
+	for _, libraryFolder := range libraryFolders {
+		compatDataPath := platform.ProcessPath(filepath.Join(libraryFolder, "steamapps", "compatdata", appID))
+		_, err := os.Stat(filepath.Join(compatDataPath, "pfx"))
+		if err == nil {
+			return compatDataPath, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err //nolint:wrapcheck
+		}
+	}
+	return "", nil
+}
+
+// getCompatToolMapping returns the compatibility tool selected for each app in the Steam config
+func getCompatToolMapping(configPath string) (map[string]string, error) {
+	configF, err := os.Open(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open steam config: %w", err)
+	}
+	defer configF.Close()
+
+	config, err := vdf.NewParser(configF).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse steam config: %w", err)
+	}
+
+	mappingData := config
+	for _, key := range []string{"InstallConfigStore", "Software", "Valve", "Steam", "CompatToolMapping"} {
+		var next map[string]interface{}
+		for k, v := range mappingData {
+			if strings.EqualFold(k, key) {
+				next, _ = v.(map[string]interface{})
+				break
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+		mappingData = next
+	}
+
+	mapping := make(map[string]string, len(mappingData))
+	for appID, toolData := range mappingData {
+		tool, ok := toolData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := tool["name"].(string); ok && name != "" {
+			mapping[appID] = name
+		}
+	}
+	return mapping, nil
+}
+
+// getProtonVersion returns the version of Proton that last ran the app, from the version file Proton writes to the compatdata,
+// along with the compatibility tool selected for the app, if it is not the default one
+func getProtonVersion(compatDataPath string, compatTool string) string {
+	var version string
+	if versionData, err := os.ReadFile(filepath.Join(compatDataPath, "version")); err == nil {
+		version = strings.TrimSpace(string(versionData))
+	} else if configInfo, err := os.ReadFile(filepath.Join(compatDataPath, "config_info")); err == nil {
+		// The first line is the version of the Proton that created the prefix
+		version = strings.TrimSpace(strings.SplitN(string(configInfo), "\n", 2)[0])
+	}
+
+	switch {
+	case compatTool == "":
+		return version
+	case version == "":
+		return compatTool
+	default:
+		return fmt.Sprintf("%s (%s)", compatTool, version)
+	}
+}
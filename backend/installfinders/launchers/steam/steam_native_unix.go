@@ -3,6 +3,7 @@
 package steam
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,7 +13,7 @@ import (
 )
 
 func init() {
-	launchers.Add("Steam", func() ([]*common.Installation, []error) {
+	launchers.Add("Steam", func(_ context.Context) ([]*common.Installation, []error) {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			return nil, []error{fmt.Errorf("failed to get user home dir: %w", err)}
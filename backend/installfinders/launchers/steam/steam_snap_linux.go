@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,7 +11,7 @@ import (
 )
 
 func init() {
-	launchers.Add("Steam-snap", func() ([]*common.Installation, []error) {
+	launchers.Add("Steam-snap", func(_ context.Context) ([]*common.Installation, []error) {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			return nil, []error{fmt.Errorf("failed to get user home dir: %w", err)}
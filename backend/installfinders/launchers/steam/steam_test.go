@@ -0,0 +1,121 @@
+package steam
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetCompatToolMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "mapped apps",
+			config: ptr(`"InstallConfigStore"
+{
+	"Software"
+	{
+		"Valve"
+		{
+			"Steam"
+			{
+				"CompatToolMapping"
+				{
+					"0"
+					{
+						"name"		"proton_experimental"
+						"config"		""
+						"priority"		"75"
+					}
+					"1690800"
+					{
+						"name"		"GE-Proton9-20"
+						"config"		""
+						"priority"		"250"
+					}
+					"526870"
+					{
+						"name"		""
+						"config"		""
+						"priority"		"250"
+					}
+				}
+			}
+		}
+	}
+}`),
+			want: map[string]string{"0": "proton_experimental", "1690800": "GE-Proton9-20"},
+		},
+		{
+			name: "key case",
+			config: ptr(`"InstallConfigStore"
+{
+	"Software"
+	{
+		"valve"
+		{
+			"Steam"
+			{
+				"compattoolmapping"
+				{
+					"1690800"
+					{
+						"name"		"proton_9"
+					}
+				}
+			}
+		}
+	}
+}`),
+			want: map[string]string{"1690800": "proton_9"},
+		},
+		{
+			name: "no mapping",
+			config: ptr(`"InstallConfigStore"
+{
+	"Software"
+	{
+	}
+}`),
+			want: nil,
+		},
+		{
+			name:   "no config",
+			config: nil,
+			want:   nil,
+		},
+		{
+			name:    "invalid config",
+			config:  ptr(`"InstallConfigStore" "value"`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.vdf")
+			if tt.config != nil {
+				if err := os.WriteFile(configPath, []byte(*tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := getCompatToolMapping(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getCompatToolMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("getCompatToolMapping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -11,7 +12,7 @@ import (
 )
 
 func init() {
-	launchers.Add("Steam", func() ([]*common.Installation, []error) {
+	launchers.Add("Steam", func(_ context.Context) ([]*common.Installation, []error) {
 		steamPath, err := getSteamPath()
 		if err != nil {
 			return nil, []error{err}
@@ -0,0 +1,182 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/andygrunwald/vdf"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/common"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/installfinders/launchers"
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/settings"
+)
+
+const dedicatedServerManifest = "appmanifest_1690800.acf"
+
+func init() {
+	launchers.Add("SteamCMD", findInstallationsSteamCMD)
+}
+
+// steamCMDRoots returns the default locations SteamCMD installs itself to
+func steamCMDRoots() []string {
+	if runtime.GOOS == "windows" {
+		roots := []string{`C:\steamcmd`, `C:\SteamCMD`}
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			roots = append(roots, filepath.Join(homeDir, "steamcmd"))
+		}
+		return roots
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{
+		filepath.Join(homeDir, "Steam"),
+		filepath.Join(homeDir, "steamcmd"),
+		filepath.Join(homeDir, ".steam", "steamcmd"),
+		filepath.Join(homeDir, ".local", "share", "Steam", "steamcmd"),
+	}
+}
+
+// findInstallationsSteamCMD finds the dedicated servers installed with SteamCMD, either in its libraries,
+// or with force_install_dir. The manifest of a force_install_dir install is in the steamapps directory of
+// that install, so those are found in the extra search roots, and in the directories directly below them
+func findInstallationsSteamCMD(ctx context.Context) ([]*common.Installation, []error) {
+	var roots []string
+	var findErrors []error
+	for _, root := range steamCMDRoots() {
+		if _, err := os.Stat(filepath.Join(root, "steamapps")); err != nil {
+			continue
+		}
+		roots = append(roots, root)
+		libraryFolders, err := getLibraryFoldersFromManifest(filepath.Join(root, "steamapps", "libraryfolders.vdf"))
+		if err != nil {
+			findErrors = append(findErrors, fmt.Errorf("failed to get library folders of %s: %w", root, err))
+			continue
+		}
+		roots = append(roots, libraryFolders...)
+	}
+	for _, searchRoot := range settings.Settings.ExtraSearchRoots {
+		roots = append(roots, searchRoot)
+		entries, err := os.ReadDir(searchRoot)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				roots = append(roots, filepath.Join(searchRoot, entry.Name()))
+			}
+		}
+	}
+
+	installs := make([]*common.Installation, 0)
+	var checkedRoots []string
+	for _, root := range roots {
+		if err := ctx.Err(); err != nil {
+			return installs, append(findErrors, fmt.Errorf("search cancelled: %w", err))
+		}
+
+		root = filepath.Clean(root)
+		alreadyChecked := false
+		for _, checkedRoot := range checkedRoots {
+			if common.OsPathEqual(checkedRoot, root) {
+				alreadyChecked = true
+				break
+			}
+		}
+		if alreadyChecked {
+			continue
+		}
+		checkedRoots = append(checkedRoots, root)
+
+		manifestPath := filepath.Join(root, "steamapps", dedicatedServerManifest)
+		if _, err := os.Stat(manifestPath); err != nil {
+			continue
+		}
+
+		install, err := getSteamCMDInstallation(root, manifestPath)
+		if err != nil {
+			findErrors = append(findErrors, err)
+			continue
+		}
+		installs = append(installs, install)
+	}
+
+	return installs, findErrors
+}
+
+func getSteamCMDInstallation(root string, manifestPath string) (*common.Installation, error) {
+	manifestF, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest file %s: %w", manifestPath, err)
+	}
+	defer manifestF.Close()
+
+	manifest, err := vdf.NewParser(manifestF).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest file %s: %w", manifestPath, err)
+	}
+
+	appState, ok := manifest["AppState"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to find AppState in manifest %s", manifestPath)
+	}
+
+	installPath, err := steamCMDInstallPath(root, appState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find install of manifest %s: %w", manifestPath, err)
+	}
+
+	branch, err := common.GetSteamBranch(appState)
+	if err != nil {
+		// Unknown beta keys are usually new branches, the install itself may still tell
+		branch = common.DetectLocalBranch(installPath)
+	}
+
+	installType, version, savedPath, err := common.GetGameInfo(installPath, common.NativePlatform())
+	if err != nil {
+		return nil, common.InstallFindError{
+			Path:  installPath,
+			Inner: err,
+		}
+	}
+
+	var launchPath []string
+	if (installType == common.InstallTypeLinuxServer && runtime.GOOS == "linux") || (installType == common.InstallTypeWindowsServer && runtime.GOOS == "windows") {
+		launchPath = []string{common.FindGameExecutable(installPath)}
+	}
+
+	return &common.Installation{
+		Path:       filepath.Clean(installPath),
+		Version:    version,
+		Type:       installType,
+		Location:   common.LocationTypeLocal,
+		Branch:     branch,
+		Launcher:   "SteamCMD",
+		LaunchPath: launchPath,
+		SavedPath:  savedPath,
+	}, nil
+}
+
+// steamCMDInstallPath returns where the app of the manifest is installed.
+// With force_install_dir, installdir is an absolute path, or the game is in the directory containing steamapps
+func steamCMDInstallPath(root string, appState map[string]interface{}) (string, error) {
+	installDir, _ := appState["installdir"].(string)
+	if installDir != "" {
+		if filepath.IsAbs(installDir) {
+			return installDir, nil
+		}
+		libraryInstallPath := filepath.Join(root, "steamapps", "common", installDir)
+		if common.FindGameExecutable(libraryInstallPath) != "" {
+			return libraryInstallPath, nil
+		}
+	}
+	if common.FindGameExecutable(root) != "" {
+		return root, nil
+	}
+	return "", fmt.Errorf("no server found for installdir %s", installDir)
+}
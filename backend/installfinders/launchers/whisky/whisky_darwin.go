@@ -1,6 +1,7 @@
 package whisky
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/url"
@@ -24,7 +25,7 @@ func init() {
 	launchers.Add("Whisky", whisky)
 }
 
-func whisky() ([]*common.Installation, []error) {
+func whisky(_ context.Context) ([]*common.Installation, []error) {
 	bottlesPath, err := getWhiskyBottlesPath()
 	if err != nil {
 		return nil, []error{fmt.Errorf("failed to get Whisky bottles path: %w", err)}
@@ -6,8 +6,11 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/spf13/viper"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/ficsitcli"
 )
 
 type migration struct {
@@ -47,6 +50,43 @@ func (m *migration) NeedsSmm2Migration() bool {
 	return false
 }
 
+type Smm2MigrationReport struct {
+	Profiles []ficsitcli.SMM2ProfileMigrationResult `json:"profiles"`
+}
+
+// MigrateSmm2Profiles imports every profile from the SMM2 profiles directory.
+// Profiles that were already imported are reported as such, so this is safe to run again
+// until the migration is marked as successful
+func (m *migration) MigrateSmm2Profiles() (*Smm2MigrationReport, error) {
+	report := &Smm2MigrationReport{
+		Profiles: []ficsitcli.SMM2ProfileMigrationResult{},
+	}
+
+	if !pathExists(m.smm2Dir) {
+		return report, nil
+	}
+
+	entries, err := os.ReadDir(m.smm2Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SMM2 profiles directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		result := ficsitcli.FicsitCLI.MigrateSMM2Profile(entry.Name(), filepath.Join(m.smm2Dir, entry.Name()))
+		slog.Info("migrated SMM2 profile", slog.String("profile", result.Profile), slog.String("status", string(result.Status)), slog.Any("warnings", result.Warnings))
+		report.Profiles = append(report.Profiles, result)
+	}
+
+	sort.Slice(report.Profiles, func(i, j int) bool {
+		return report.Profiles[i].Profile < report.Profiles[j].Profile
+	})
+
+	return report, nil
+}
+
 func (m *migration) MarkSmm2MigrationSuccess() error {
 	file, err := os.Create(Migration.migrationSuccessMarkerPath)
 	if err != nil {
@@ -0,0 +1,235 @@
+package settings
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mircearoata/pubgrub-go/pubgrub/semver"
+	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
+
+	"github.com/satisfactorymodding/SatisfactoryModManager/backend/common"
+)
+
+type IgnoreRuleType string
+
+const (
+	// IgnoreVersion ignores the update to exactly Version
+	IgnoreVersion IgnoreRuleType = "version"
+	// IgnoreBelow ignores the updates to any version lower than Version
+	IgnoreBelow IgnoreRuleType = "below"
+	// IgnoreUntil ignores all updates until the Until date
+	IgnoreUntil IgnoreRuleType = "until"
+)
+
+var AllIgnoreRuleTypes = []struct {
+	Value  IgnoreRuleType
+	TSName string
+}{
+	{IgnoreVersion, "VERSION"},
+	{IgnoreBelow, "BELOW"},
+	{IgnoreUntil, "UNTIL"},
+}
+
+type IgnoreRule struct {
+	ID      string         `json:"id"`
+	Mod     string         `json:"mod"`
+	Type    IgnoreRuleType `json:"type"`
+	Version string         `json:"version,omitempty"`
+	Until   *time.Time     `json:"until,omitempty"`
+	// Installs and Profiles limit the rule to updates on those installs or profiles. Empty means everywhere
+	Installs []string `json:"installs,omitempty"`
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+func (r IgnoreRule) validate() error {
+	if r.Mod == "" {
+		return fmt.Errorf("missing mod")
+	}
+	switch r.Type {
+	case IgnoreVersion, IgnoreBelow:
+		if _, err := semver.NewVersion(r.Version); err != nil {
+			return fmt.Errorf("invalid version %s: %w", r.Version, err)
+		}
+	case IgnoreUntil:
+		if r.Until == nil {
+			return fmt.Errorf("missing date")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %s", r.Type)
+	}
+	return nil
+}
+
+func (r IgnoreRule) expired() bool {
+	return r.Type == IgnoreUntil && r.Until != nil && time.Now().After(*r.Until)
+}
+
+func (r IgnoreRule) matches(modReference string, version string, install string, profile string) bool {
+	if r.Mod != modReference || r.expired() {
+		return false
+	}
+	if len(r.Installs) > 0 && !slices.Contains(r.Installs, install) {
+		return false
+	}
+	if len(r.Profiles) > 0 && !slices.Contains(r.Profiles, profile) {
+		return false
+	}
+	switch r.Type {
+	case IgnoreVersion:
+		return r.Version == version
+	case IgnoreBelow:
+		ruleVersion, err := semver.NewVersion(r.Version)
+		if err != nil {
+			return false
+		}
+		updateVersion, err := semver.NewVersion(version)
+		if err != nil {
+			return false
+		}
+		return updateVersion.Compare(ruleVersion) < 0
+	case IgnoreUntil:
+		return true
+	}
+	return false
+}
+
+// ignoreRulesMutex guards IgnoreRules, since updates are checked and expired rules cleaned up from background goroutines
+var ignoreRulesMutex sync.Mutex
+
+// IsUpdateIgnored checks whether any rule ignores the update of the mod to the version, on the install and profile
+func (s *settings) IsUpdateIgnored(modReference string, version string, install string, profile string) bool {
+	return len(s.MatchingIgnoreRules(modReference, version, install, profile)) > 0
+}
+
+// MatchingIgnoreRules returns the IDs of the rules ignoring the update of the mod to the version, on the install and profile
+func (s *settings) MatchingIgnoreRules(modReference string, version string, install string, profile string) []string {
+	ignoreRulesMutex.Lock()
+	defer ignoreRulesMutex.Unlock()
+
+	var ids []string
+	for _, rule := range s.IgnoreRules {
+		if rule.matches(modReference, version, install, profile) {
+			ids = append(ids, rule.ID)
+		}
+	}
+	return ids
+}
+
+func (s *settings) GetIgnoreRules() []IgnoreRule {
+	ignoreRulesMutex.Lock()
+	defer ignoreRulesMutex.Unlock()
+
+	return slices.Clone(s.IgnoreRules)
+}
+
+func (s *settings) AddIgnoreRule(rule IgnoreRule) (*IgnoreRule, error) {
+	if err := rule.validate(); err != nil {
+		return nil, fmt.Errorf("invalid ignore rule: %w", err)
+	}
+	rule.ID = uuid.NewString()
+	s.updateIgnoreRules(func(rules []IgnoreRule) []IgnoreRule {
+		return append(rules, rule)
+	})
+	return &rule, nil
+}
+
+func (s *settings) RemoveIgnoreRule(id string) {
+	s.RemoveIgnoreRules([]string{id})
+}
+
+// RemoveIgnoreRules removes the rules with the IDs, such as the ones MatchingIgnoreRules returned for an update
+func (s *settings) RemoveIgnoreRules(ids []string) {
+	s.updateIgnoreRules(func(rules []IgnoreRule) []IgnoreRule {
+		return slices.DeleteFunc(rules, func(rule IgnoreRule) bool {
+			return slices.Contains(ids, rule.ID)
+		})
+	})
+}
+
+// SetUpdateIgnore ignores the update of the mod to exactly the version, everywhere. Returns the ID of the created rule
+func (s *settings) SetUpdateIgnore(modReference string, version string) string {
+	rule, err := s.AddIgnoreRule(IgnoreRule{
+		Mod:     modReference,
+		Type:    IgnoreVersion,
+		Version: version,
+	})
+	if err != nil {
+		slog.Error("failed to ignore update", slog.String("mod", modReference), slog.String("version", version), slog.Any("error", err))
+		return ""
+	}
+	return rule.ID
+}
+
+// CleanupIgnoreRules removes the rules that expired
+func (s *settings) CleanupIgnoreRules() {
+	ignoreRulesMutex.Lock()
+	count := len(s.IgnoreRules)
+	s.IgnoreRules = slices.DeleteFunc(s.IgnoreRules, IgnoreRule.expired)
+	removed := count - len(s.IgnoreRules)
+	ignoreRulesMutex.Unlock()
+
+	if removed == 0 {
+		return
+	}
+	slog.Info("removed expired ignore rules", slog.Int("count", removed))
+	_ = SaveSettings()
+	s.emitIgnoreRules()
+}
+
+// RenameIgnoreRulesProfile keeps the rules limited to a profile applying after the profile is renamed
+func (s *settings) RenameIgnoreRulesProfile(oldName string, newName string) {
+	ignoreRulesMutex.Lock()
+	changed := false
+	for i := range s.IgnoreRules {
+		if idx := slices.Index(s.IgnoreRules[i].Profiles, oldName); idx != -1 {
+			s.IgnoreRules[i].Profiles[idx] = newName
+			changed = true
+		}
+	}
+	ignoreRulesMutex.Unlock()
+
+	if changed {
+		_ = SaveSettings()
+		s.emitIgnoreRules()
+	}
+}
+
+// updateIgnoreRules replaces the rules with the result of update, then saves and emits them
+func (s *settings) updateIgnoreRules(update func([]IgnoreRule) []IgnoreRule) {
+	ignoreRulesMutex.Lock()
+	s.IgnoreRules = update(s.IgnoreRules)
+	ignoreRulesMutex.Unlock()
+
+	_ = SaveSettings()
+	s.emitIgnoreRules()
+}
+
+// migrateIgnoredUpdates converts the ignored versions of older versions to rules
+func (s *settings) migrateIgnoredUpdates() {
+	if len(s.IgnoredUpdates) == 0 {
+		return
+	}
+	for modReference, versions := range s.IgnoredUpdates {
+		for _, version := range versions {
+			s.IgnoreRules = append(s.IgnoreRules, IgnoreRule{
+				ID:      uuid.NewString(),
+				Mod:     modReference,
+				Type:    IgnoreVersion,
+				Version: version,
+			})
+		}
+	}
+	s.IgnoredUpdates = nil
+	_ = SaveSettings()
+}
+
+func (s *settings) emitIgnoreRules() {
+	if common.AppContext == nil {
+		return
+	}
+	wailsRuntime.EventsEmit(common.AppContext, "ignoreRules", s.GetIgnoreRules())
+}
@@ -0,0 +1,149 @@
+package settings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIgnoreRuleMatches(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		rule    IgnoreRule
+		mod     string
+		version string
+		install string
+		profile string
+		want    bool
+	}{
+		{
+			name:    "exact version",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreVersion, Version: "3.7.0"},
+			mod:     "SML",
+			version: "3.7.0",
+			want:    true,
+		},
+		{
+			name:    "other version",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreVersion, Version: "3.7.0"},
+			mod:     "SML",
+			version: "3.7.1",
+			want:    false,
+		},
+		{
+			name:    "other mod",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreVersion, Version: "3.7.0"},
+			mod:     "ContentLib",
+			version: "3.7.0",
+			want:    false,
+		},
+		{
+			name:    "below lower version",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreBelow, Version: "4.0.0"},
+			mod:     "SML",
+			version: "3.9.9",
+			want:    true,
+		},
+		{
+			name:    "below same version",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreBelow, Version: "4.0.0"},
+			mod:     "SML",
+			version: "4.0.0",
+			want:    false,
+		},
+		{
+			name:    "below invalid update version",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreBelow, Version: "4.0.0"},
+			mod:     "SML",
+			version: "not-a-version",
+			want:    false,
+		},
+		{
+			name:    "until in the future",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreUntil, Until: &future},
+			mod:     "SML",
+			version: "3.7.0",
+			want:    true,
+		},
+		{
+			name:    "until expired",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreUntil, Until: &past},
+			mod:     "SML",
+			version: "3.7.0",
+			want:    false,
+		},
+		{
+			name:    "limited to the install",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreVersion, Version: "3.7.0", Installs: []string{"C:\\Game"}},
+			mod:     "SML",
+			version: "3.7.0",
+			install: "C:\\Game",
+			want:    true,
+		},
+		{
+			name:    "limited to another install",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreVersion, Version: "3.7.0", Installs: []string{"C:\\Game"}},
+			mod:     "SML",
+			version: "3.7.0",
+			install: "D:\\Game",
+			want:    false,
+		},
+		{
+			name:    "limited to another profile",
+			rule:    IgnoreRule{Mod: "SML", Type: IgnoreVersion, Version: "3.7.0", Profiles: []string{"Default"}},
+			mod:     "SML",
+			version: "3.7.0",
+			profile: "Other",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(tt.mod, tt.version, tt.install, tt.profile); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreRuleExpired(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		rule IgnoreRule
+		want bool
+	}{
+		{name: "until in the past", rule: IgnoreRule{Type: IgnoreUntil, Until: &past}, want: true},
+		{name: "until in the future", rule: IgnoreRule{Type: IgnoreUntil, Until: &future}, want: false},
+		{name: "until without date", rule: IgnoreRule{Type: IgnoreUntil}, want: false},
+		{name: "version rules never expire", rule: IgnoreRule{Type: IgnoreVersion, Version: "1.0.0", Until: &past}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.expired(); got != tt.want {
+				t.Errorf("expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchingIgnoreRules(t *testing.T) {
+	s := &settings{
+		IgnoreRules: []IgnoreRule{
+			{ID: "exact", Mod: "SML", Type: IgnoreVersion, Version: "3.7.0"},
+			{ID: "below", Mod: "SML", Type: IgnoreBelow, Version: "4.0.0"},
+			{ID: "other", Mod: "ContentLib", Type: IgnoreVersion, Version: "3.7.0"},
+		},
+	}
+
+	got := s.MatchingIgnoreRules("SML", "3.7.0", "", "")
+	if len(got) != 2 || got[0] != "exact" || got[1] != "below" {
+		t.Errorf("MatchingIgnoreRules() = %v, want [exact below]", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	psUtilDisk "github.com/shirou/gopsutil/v3/disk"
 	"github.com/spf13/viper"
@@ -35,6 +37,19 @@ var (
 	UpdateAsk      UpdateCheckMode = "ask"
 )
 
+// AutoUpdateTimeFormat is the format of the times of day in AutoUpdateSchedule
+const AutoUpdateTimeFormat = "15:04"
+
+type AutoUpdateSchedule struct {
+	Enabled bool `json:"enabled"`
+	// IntervalHours runs the updates every that many hours. 0 only uses Times
+	IntervalHours int `json:"intervalHours,omitempty"`
+	// Times are the local times of day to run the updates at
+	Times []string `json:"times,omitempty"`
+	// Installs are the installs opted in to automatic updates
+	Installs []string `json:"installs,omitempty"`
+}
+
 type settings struct {
 	WindowPosition        *utils.Position `json:"windowPosition,omitempty"`
 	Maximized             bool            `json:"maximized,omitempty"`
@@ -50,10 +65,19 @@ type settings struct {
 
 	RemoteNames map[string]string `json:"remoteNames,omitempty"`
 
-	QueueAutoStart      bool                `json:"queueAutoStart"`
-	IgnoredUpdates      map[string][]string `json:"ignoredUpdates,omitempty"`
-	UpdateCheckMode     UpdateCheckMode     `json:"updateCheckMode,omitempty"`
-	ViewedAnnouncements []string            `json:"viewedAnnouncements,omitempty"`
+	// ExtraSearchRoots are directories searched for installs, in addition to the launchers' own locations
+	ExtraSearchRoots []string `json:"extraSearchRoots,omitempty"`
+	// LocalServers are dedicated server directories added by path, outside any launcher
+	LocalServers []string `json:"localServers,omitempty"`
+
+	QueueAutoStart      bool               `json:"queueAutoStart"`
+	IgnoreRules         []IgnoreRule       `json:"ignoreRules,omitempty"`
+	UpdateCheckMode     UpdateCheckMode    `json:"updateCheckMode,omitempty"`
+	ViewedAnnouncements []string           `json:"viewedAnnouncements,omitempty"`
+	AutoUpdateSchedule  AutoUpdateSchedule `json:"autoUpdateSchedule,omitempty"`
+
+	// Deprecated: IgnoredUpdates is only read to migrate to IgnoreRules
+	IgnoredUpdates map[string][]string `json:"ignoredUpdates,omitempty"`
 
 	Offline bool `json:"offline,omitempty"`
 
@@ -91,7 +115,7 @@ var Settings = &settings{
 	RemoteNames: map[string]string{},
 
 	QueueAutoStart:      true,
-	IgnoredUpdates:      map[string][]string{},
+	IgnoreRules:         []IgnoreRule{},
 	UpdateCheckMode:     UpdateOnLaunch,
 	ViewedAnnouncements: []string{},
 
@@ -224,40 +248,54 @@ func (s *settings) SetQueueAutoStart(value bool) {
 	_ = SaveSettings()
 }
 
-func (s *settings) GetIgnoredUpdates() map[string][]string {
-	return s.IgnoredUpdates
+func (s *settings) GetUpdateCheckMode() UpdateCheckMode {
+	return s.UpdateCheckMode
 }
 
-func (s *settings) SetUpdateIgnore(modReference string, version string) {
-	s.IgnoredUpdates[modReference] = append(s.IgnoredUpdates[modReference], version)
+func (s *settings) SetUpdateCheckMode(value UpdateCheckMode) {
+	s.UpdateCheckMode = value
 	_ = SaveSettings()
-	wailsRuntime.EventsEmit(common.AppContext, "ignoredUpdates", s.IgnoredUpdates)
 }
 
-func (s *settings) SetUpdateUnignore(modReference string, version string) {
-	versions := s.IgnoredUpdates[modReference]
-	idx := -1
-	for i, v := range versions {
-		if v == version {
-			idx = i
-			break
-		}
+func (s *settings) GetAutoUpdateSchedule() AutoUpdateSchedule {
+	return s.AutoUpdateSchedule
+}
+
+func (s *settings) SetAutoUpdateSchedule(schedule AutoUpdateSchedule) error {
+	if schedule.IntervalHours < 0 {
+		return fmt.Errorf("invalid interval %d", schedule.IntervalHours)
 	}
-	if idx == -1 {
-		return
+	for _, t := range schedule.Times {
+		if _, err := time.Parse(AutoUpdateTimeFormat, t); err != nil {
+			return fmt.Errorf("invalid time %s: %w", t, err)
+		}
 	}
-	s.IgnoredUpdates[modReference] = append(versions[:idx], versions[idx+1:]...)
+	s.AutoUpdateSchedule = schedule
 	_ = SaveSettings()
-	wailsRuntime.EventsEmit(common.AppContext, "ignoredUpdates", s.IgnoredUpdates)
+	wailsRuntime.EventsEmit(common.AppContext, "autoUpdateSchedule", s.AutoUpdateSchedule)
+	return nil
 }
 
-func (s *settings) GetUpdateCheckMode() UpdateCheckMode {
-	return s.UpdateCheckMode
+func (s *settings) GetExtraSearchRoots() []string {
+	return s.ExtraSearchRoots
 }
 
-func (s *settings) SetUpdateCheckMode(value UpdateCheckMode) {
-	s.UpdateCheckMode = value
+// SetExtraSearchRoots sets the directories searched for installs. They are searched the next time SMM starts
+func (s *settings) SetExtraSearchRoots(roots []string) error {
+	cleanRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if !filepath.IsAbs(root) {
+			return fmt.Errorf("search root must be an absolute path: %s", root)
+		}
+		root = filepath.Clean(root)
+		if !slices.Contains(cleanRoots, root) {
+			cleanRoots = append(cleanRoots, root)
+		}
+	}
+	s.ExtraSearchRoots = cleanRoots
 	_ = SaveSettings()
+	wailsRuntime.EventsEmit(common.AppContext, "extraSearchRoots", s.ExtraSearchRoots)
+	return nil
 }
 
 func (s *settings) GetViewedAnnouncements() []string {
@@ -450,11 +488,16 @@ func LoadSettings() error {
 		}
 	}
 
+	Settings.migrateIgnoredUpdates()
+	Settings.CleanupIgnoreRules()
+
 	return nil
 }
 
 func SaveSettings() error {
+	ignoreRulesMutex.Lock()
 	settingsFile, err := utils.JSONMarshal(Settings, 2)
+	ignoreRulesMutex.Unlock()
 	if err != nil {
 		return fmt.Errorf("failed to marshal settings: %w", err)
 	}
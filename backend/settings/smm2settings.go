@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/google/uuid"
+
 	"github.com/satisfactorymodding/SatisfactoryModManager/backend/utils"
 )
 
@@ -87,7 +89,12 @@ func readSMM2Settings(data []byte) error {
 
 	if s.IgnoredUpdates != nil {
 		for _, ignoredUpdate := range *s.IgnoredUpdates {
-			Settings.IgnoredUpdates[ignoredUpdate.Item] = append(Settings.IgnoredUpdates[ignoredUpdate.Item], ignoredUpdate.Version)
+			Settings.IgnoreRules = append(Settings.IgnoreRules, IgnoreRule{
+				ID:      uuid.NewString(),
+				Mod:     ignoredUpdate.Item,
+				Type:    IgnoreVersion,
+				Version: ignoredUpdate.Version,
+			})
 		}
 	}
 
@@ -5,9 +5,11 @@ import ServerManager from './ServerManager.svelte';
 import AddProfile from './profiles/AddProfile.svelte';
 import ImportProfile from './profiles/ImportProfile.svelte';
 import CacheLocationPicker from './settings/CacheLocationPicker.svelte';
+import ExtraSearchRoots from './settings/ExtraSearchRoots.svelte';
 import Proxy from './settings/Proxy.svelte';
 import SMMUpdateDownload from './smmUpdate/SMMUpdateDownload.svelte';
 import SMMUpdateReady from './smmUpdate/SMMUpdateReady.svelte';
+import UndoHistory from './updates/UndoHistory.svelte';
 import UpdatesModal from './updates/UpdatesModal.svelte';
 
 // We can only store here modals (or modal instances) that do not require additional props
@@ -18,8 +20,10 @@ export const modalRegistry = {
   addProfile: { ref: AddProfile } as ModalComponent,
   importProfile: { ref: ImportProfile } as ModalComponent,
   modUpdates: { ref: UpdatesModal } as ModalComponent,
+  undoHistory: { ref: UndoHistory } as ModalComponent,
   smmUpdateDownload: { ref: SMMUpdateDownload } as ModalComponent,
   smmUpdateReady: { ref: SMMUpdateReady } as ModalComponent,
   proxy: { ref: Proxy } as ModalComponent,
+  extraSearchRoots: { ref: ExtraSearchRoots } as ModalComponent,
 };
 							
\ No newline at end of file
@@ -1,7 +1,6 @@
 import { derived, get, writable } from 'svelte/store';
 
 import { isLaunchingGame } from './generalStore';
-import { ignoredUpdates } from './settingsStore';
 import { binding, bindingTwoWay } from './wailsStoreBindings';
 
 import { modActionsQueue, queuedMods } from '$lib/store/actionQueue';
@@ -13,13 +12,16 @@ import {
   GetInstallations,
   GetInstallationsMetadata,
   GetInvalidInstalls,
+  GetLocalServers,
   GetModsEnabled,
   GetProfiles,
   GetRemoteInstallations,
   GetSelectedInstall,
   GetSelectedInstallLockfileMods,
   GetSelectedInstallProfileMods,
+  GetSelectedProfileInstallMismatch,
   GetSelectedProfile,
+  GetUndoHistory,
   SelectInstall,
   SelectedProfileTargets,
   SetModsEnabled,
@@ -36,9 +38,11 @@ export const selectedInstall = bindingTwoWay(null, { initialGet: () => GetSelect
 export const selectedInstallMetadata = derived([installsMetadata, selectedInstall], ([$installsMetadata, $selectedInstallPath]) => {
   return $installsMetadata[$selectedInstallPath ?? '__invalid__install__'] ?? null;
 });
+export const profileInstallMismatch = binding<ficsitcli.ProfileInstallMismatch | null>(null, { initialGet: GetSelectedProfileInstallMismatch, updateEvent: 'profileInstallMismatch' });
 export const selectedProfileTargets = binding<Record<string, string[]>>({}, { initialGet: SelectedProfileTargets, updateEvent: 'selectedProfileTargets' });
 
 export const remoteServers = binding([], { initialGet: () => GetRemoteInstallations(), updateEvent: 'remoteServers', allowNull: false });
+export const localServers = binding([], { initialGet: GetLocalServers, updateEvent: 'localServers', allowNull: false });
 
 export const profiles = binding([], { initialGet: GetProfiles, updateEvent: 'profiles' });
 export const selectedProfile = bindingTwoWay(null, { initialGet: GetSelectedProfile, updateEvent: 'selectedProfile', allowNull: false }, { updateFunction: SetProfile });
@@ -60,16 +64,22 @@ export const lockfileMods = binding({}, { initialGet: GetSelectedInstallLockfile
 
 export const progress = binding<ficsitcli.Progress | null>(null, { updateEvent: 'progress' });
 
+export const installsProgress = binding<Record<string, ficsitcli.Progress>>({}, { updateEvent: 'installsProgress', allowNull: false });
+
+export const selectedInstallProgress = derived([installsProgress, selectedInstall], ([$installsProgress, $selectedInstall]) => ($selectedInstall ? $installsProgress[$selectedInstall] : null) ?? null);
+
+export const undoHistory = binding<ficsitcli.UndoEntry[]>([], { initialGet: GetUndoHistory, updateEvent: 'undoHistory', allowNull: false });
+
 export const favoriteMods = binding<string[]>([], { initialGet: GetFavoriteMods, updateEvent: 'favoriteMods' });
 
 export const isGameRunning = binding(false, { updateEvent: 'isGameRunning', allowNull: false });
 
-export const canModify = derived([isGameRunning, progress, isLaunchingGame, installs, selectedInstallMetadata, queuedMods], ([$isGameRunning, $progress, $isLaunchingGame, $installs, $selectedInstallMetadata, $queuedMods]) => {
-  return !$isGameRunning && !$progress && !$isLaunchingGame && $installs.length > 0 && $selectedInstallMetadata?.state === ficsitcli.InstallState.VALID && $queuedMods.length <= 0;
+export const canModify = derived([isGameRunning, selectedInstallProgress, isLaunchingGame, installs, selectedInstallMetadata, queuedMods], ([$isGameRunning, $selectedInstallProgress, $isLaunchingGame, $installs, $selectedInstallMetadata, $queuedMods]) => {
+  return !$isGameRunning && !$selectedInstallProgress && !$isLaunchingGame && $installs.length > 0 && $selectedInstallMetadata?.state === ficsitcli.InstallState.VALID && $queuedMods.length <= 0;
 });
 
-export const canChangeInstall = derived([isGameRunning, progress, isLaunchingGame, installs, queuedMods], ([$isGameRunning, $progress, $isLaunchingGame, $installs, $queuedMods]) => {
-  return !$isGameRunning && !$progress && !$isLaunchingGame && $installs.length > 0 && $queuedMods.length <= 0;
+export const canChangeInstall = derived([isGameRunning, selectedInstallProgress, isLaunchingGame, installs, queuedMods], ([$isGameRunning, $selectedInstallProgress, $isLaunchingGame, $installs, $queuedMods]) => {
+  return !$isGameRunning && !$selectedInstallProgress && !$isLaunchingGame && $installs.length > 0 && $queuedMods.length <= 0;
 });
 
 export const canInstallMods = derived([isGameRunning, isLaunchingGame, installs, selectedInstallMetadata], ([$isGameRunning, $isLaunchingGame, $installs, $selectedInstallMetadata]) => {
@@ -77,7 +87,7 @@ export const canInstallMods = derived([isGameRunning, isLaunchingGame, installs,
 });
 
 export const updates = writable<ficsitcli.Update[]>([]);
-export const unignoredUpdates = derived([updates, ignoredUpdates], ([$updates, $ignoredUpdates]) => $updates.filter((u) => !$ignoredUpdates[u.item]?.includes(u.newVersion)));
+export const unignoredUpdates = derived(updates, ($updates) => $updates.filter((u) => !u.ignored));
 export const updateCheckInProgress = writable(false);
 
 export async function checkForUpdates() {
@@ -97,8 +107,12 @@ modActionsQueue.drain(() => {
   checkForUpdates().catch(console.error);
 });
 
-export const progressTitle = derived(progress, ($progress) => {
+export const progressTitle = derived([progress, installsProgress], ([$progress, $installsProgress]) => {
   if (!$progress) return '';
+  const runningActions = new Set(Object.values($installsProgress).map((p) => p.installs.join('\n'))).size;
+  if (runningActions > 1) {
+    return `Running ${runningActions} operations`;
+  }
   switch ($progress.action) {
     case ficsitcli.Action.SELECT_INSTALL: {
       const install = get(installsMetadata)[$progress.item.name];
@@ -114,6 +128,10 @@ export const progressTitle = derived(progress, ($progress) => {
       return `Importing profile ${$progress.item.name}`;
     case ficsitcli.Action.APPLY:
       return `Applying ${$progress.item.name}`;
+    case ficsitcli.Action.AUTO_UPDATE:
+      return 'Running scheduled mod updates';
+    case ficsitcli.Action.UNDO:
+      return 'Undoing last operation';
   }
 });
 
@@ -158,6 +176,7 @@ const placeholderProgressMessage = derived(progress, ($progress) => {
     case ficsitcli.Action.IMPORT_PROFILE:
       return `Validating install... ${isRemoteInstall ? '(this may take a while for remote servers)' : ''}`;
     case ficsitcli.Action.UPDATE:
+    case ficsitcli.Action.AUTO_UPDATE:
       return 'Updating...';
     case ficsitcli.Action.APPLY:
       return 'Applying...';
@@ -3,10 +3,12 @@ import { binding, bindingTwoWay, bindingTwoWayNoExcept } from './wailsStoreBindi
 import type { LaunchButtonType, ViewType } from '$lib/wailsTypesExtensions';
 import { GetVersion } from '$wailsjs/go/app/app';
 import { GetOffline, SetOffline } from '$wailsjs/go/ficsitcli/ficsitCLI';
+import type { settings } from '$wailsjs/go/models';
 import {
   GetCacheDir,
   GetDebug,
-  GetIgnoredUpdates,
+  GetExtraSearchRoots,
+  GetIgnoreRules,
   GetKonami,
   GetLanguage,
   GetLaunchButton,
@@ -45,7 +47,9 @@ export const updateCheckMode = bindingTwoWayNoExcept<'launch'|'exit'|'ask'>('lau
 
 export const viewedAnnouncements = binding<string[]>([], { initialGet: GetViewedAnnouncements, updateEvent: 'viewedAnnouncements' });
 
-export const ignoredUpdates = binding<Record<string, string[]>>({}, { initialGet: GetIgnoredUpdates, updateEvent: 'ignoredUpdates' });
+export const ignoreRules = binding<settings.IgnoreRule[]>([], { initialGet: GetIgnoreRules, updateEvent: 'ignoreRules' });
+
+export const extraSearchRoots = binding<string[]>([], { initialGet: GetExtraSearchRoots, updateEvent: 'extraSearchRoots', allowNull: false });
 
 export const cacheDir = bindingTwoWay<string, null>(null, { initialGet: GetCacheDir, updateEvent: 'cacheDir' }, { updateFunction: SetCacheDir });
 
@@ -207,7 +207,11 @@ func main() {
 			app.App.WatchWindow() //nolint:contextcheck
 			go websocket.ListenAndServeWebsocket()
 
-			ficsitcli.FicsitCLI.StartGameRunningWatcher() //nolint:contextcheck
+			ficsitcli.FicsitCLI.StartGameRunningWatcher()         //nolint:contextcheck
+			ficsitcli.FicsitCLI.StartProfileSubscriptionWatcher() //nolint:contextcheck
+			ficsitcli.FicsitCLI.StartUpdatesCheckWatcher()        //nolint:contextcheck
+			ficsitcli.FicsitCLI.StartAutoUpdateScheduler()        //nolint:contextcheck
+			ficsitcli.FicsitCLI.StartInstallationsWatcher()       //nolint:contextcheck
 		},
 		OnDomReady: func(_ context.Context) {
 			// OnDomReady is called on every refresh
@@ -215,6 +219,7 @@ func main() {
 		},
 		OnShutdown: func(_ context.Context) {
 			app.App.StopWindowWatcher()
+			ficsitcli.FicsitCLI.StopInstallationsWatcher()
 		},
 		Bind: []interface{}{
 			app.App,
@@ -230,6 +235,14 @@ func main() {
 			common.AllLocationTypes,
 			ficsitcli.AllInstallationStates,
 			ficsitcli.AllActionTypes,
+			ficsitcli.AllMergeStrategies,
+			ficsitcli.AllProfileImportModes,
+			ficsitcli.AllSMM2ProfileMigrationStatuses,
+			ficsitcli.AllProfileSubscriptionChangeTypes,
+			ficsitcli.AllDependencyChangeTypes,
+			ficsitcli.AllFileChangeTypes,
+			ficsitcli.AllDiagnosticSeverities,
+			settings.AllIgnoreRuleTypes,
 		},
 		Logger: backend.WailsZeroLogLogger{},
 		Debug: options.Debug{
@@ -318,6 +331,10 @@ func init() {
 
 	viper.Set("default-cache-dir", cacheDir)
 
+	// Stored alongside the ficsit-cli profiles
+	viper.Set("profile-templates-file", "profile-templates.json")
+	viper.Set("profile-metadata-file", "profile-metadata.json")
+
 	viper.Set("websocket-port", 33642)
 
 	viper.Set("github-release-repo", "satisfactorymodding/SatisfactoryModManager")